@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/myfarism/lamarr-api/internal/ai"
 	"github.com/myfarism/lamarr-api/internal/handler"
 	"github.com/myfarism/lamarr-api/internal/middleware"
 	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/internal/task"
 	"github.com/myfarism/lamarr-api/pkg/database"
 	"github.com/myfarism/lamarr-api/pkg/firebase"
+	"github.com/myfarism/lamarr-api/pkg/queue"
 )
 
 func main() {
@@ -21,14 +27,61 @@ func main() {
 
 	database.Connect()
 	firebase.Init()
+	ai.Init()
+	ai.InitPrompts()
+	ai.InitEmbedder()
+	ai.InitCache(database.DB)
+	queue.Connect()
 
 	// Auto migrate semua model
 	database.DB.AutoMigrate(
 		&model.User{},
 		&model.Job{},
 		&model.JobTimeline{},
+		&model.CvEmbedding{},
+		&model.JobEmbedding{},
+		&model.Interview{},
+		&model.Contact{},
+		&model.ContactInteraction{},
+		&model.Company{},
+		&model.Tag{},
+		&model.CvFile{},
+		&model.CvVersion{},
+		&model.CvVersionEmbedding{},
+		&model.CoverLetter{},
+		&model.AICacheEntry{},
+		&model.JobAnalysis{},
+		&model.ParseResult{},
+		&model.ExchangeRate{},
 	)
 
+	// User lama yang CV-nya masih cuma di User.CvText
+	service.InBackground("cv version backfill", func(ctx context.Context) error {
+		return service.BackfillDefaultCvVersions(0)
+	})
+
+	// Job lama yang belum terhubung ke company
+	service.InBackground("company backfill", func(ctx context.Context) error {
+		return service.BackfillJobCompanies()
+	})
+
+	// Job lama yang gajinya belum pernah dinormalisasi
+	service.InBackground("salary normalization backfill", func(ctx context.Context) error {
+		return service.BackfillSalaryNormalization()
+	})
+
+	// Ghost sweep jalan di proses server kalau tidak ada worker yang menjadwalkannya
+	var ghostSweepScheduled func() bool
+	if queue.Enabled() {
+		ghostSweepScheduled = func() bool { return queue.Scheduled(task.TypeGhostSweep) }
+	}
+	service.StartGhostSweeper(6*time.Hour, ghostSweepScheduled)
+
+	// Tanpa Redis tidak ada worker, jadi purge cache AI jalan di proses server
+	if !queue.Enabled() {
+		service.StartCachePurger(24 * time.Hour)
+	}
+
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
@@ -46,23 +99,92 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok", "service": "lamarr-api"})
 	})
 
+	// Metrics cache AI (tanpa data user)
+	r.GET("/metrics/ai-cache", handler.GetAICacheMetrics)
+
+	// Feed ICS publik, dilindungi secret token per user
+	r.GET("/calendar/:token", handler.ServeCalendar)
+
 	// Protected routes
 	api := r.Group("/api")
 	api.Use(middleware.AuthRequired())
 	{
 		api.GET("/me", handler.GetMe)
 		api.PATCH("/me/cv", handler.UpdateCV)
+		api.POST("/me/cv/upload", handler.UploadCV)
+		api.GET("/me/cv/file", handler.DownloadCV)
+		api.PATCH("/me/settings", handler.UpdateSettings)
+		api.GET("/me/calendar", handler.GetCalendarFeed)
+		api.POST("/me/calendar/rotate", handler.RotateCalendarFeed)
 
 		// Job routes
 		jobs := api.Group("/jobs")
 		{
 			jobs.GET("", handler.GetJobs)
 			jobs.GET("/stats", handler.GetStats)
+			jobs.GET("/semantic-search", handler.SemanticSearchJobs)
+			jobs.GET("/export", handler.ExportJobs)
+			jobs.POST("/import", handler.ImportJobs)
+			jobs.POST("/import/trello", handler.ImportTrello)
 			jobs.GET("/:id", handler.GetJob)
 			jobs.POST("", handler.CreateJob)
 			jobs.PATCH("/:id", handler.UpdateJob)
 			jobs.PATCH("/:id/status", handler.UpdateJobStatus)
 			jobs.DELETE("/:id", handler.DeleteJob)
+			jobs.GET("/:id/interviews", handler.GetJobInterviews)
+			jobs.POST("/:id/interviews", handler.CreateInterview)
+			jobs.PUT("/:id/tags", handler.SetJobTags)
+			jobs.GET("/:id/cover-letters", handler.GetCoverLetters)
+			jobs.PATCH("/:id/cover-letters/:letterId", handler.UpdateCoverLetter)
+			jobs.DELETE("/:id/cover-letters/:letterId", handler.DeleteCoverLetter)
+			jobs.GET("/:id/analyses", handler.GetJobAnalyses)
+		}
+
+		cvVersions := api.Group("/cv-versions")
+		{
+			cvVersions.GET("", handler.GetCvVersions)
+			cvVersions.POST("", handler.CreateCvVersion)
+			cvVersions.GET("/:id", handler.GetCvVersion)
+			cvVersions.PATCH("/:id", handler.UpdateCvVersion)
+			cvVersions.POST("/:id/default", handler.SetDefaultCvVersion)
+			cvVersions.DELETE("/:id", handler.DeleteCvVersion)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.GET("", handler.GetTags)
+			tags.POST("", handler.CreateTag)
+			tags.PATCH("/:id", handler.UpdateTag)
+			tags.DELETE("/:id", handler.DeleteTag)
+		}
+
+		companies := api.Group("/companies")
+		{
+			companies.GET("", handler.GetCompanies)
+			companies.GET("/:id", handler.GetCompany)
+			companies.PATCH("/:id", handler.UpdateCompany)
+			companies.POST("/:id/merge", handler.MergeCompany)
+		}
+
+		contacts := api.Group("/contacts")
+		{
+			contacts.GET("", handler.GetContacts)
+			contacts.POST("", handler.CreateContact)
+			contacts.GET("/:id", handler.GetContact)
+			contacts.PATCH("/:id", handler.UpdateContact)
+			contacts.DELETE("/:id", handler.DeleteContact)
+			contacts.POST("/:id/jobs/:jobId", handler.LinkContactJob)
+			contacts.DELETE("/:id/jobs/:jobId", handler.UnlinkContactJob)
+			contacts.GET("/:id/interactions", handler.GetContactInteractions)
+			contacts.POST("/:id/interactions", handler.CreateContactInteraction)
+			contacts.DELETE("/:id/interactions/:interactionId", handler.DeleteContactInteraction)
+		}
+
+		interviews := api.Group("/interviews")
+		{
+			interviews.GET("", handler.GetInterviews)
+			interviews.PATCH("/:id", handler.UpdateInterview)
+			interviews.DELETE("/:id", handler.DeleteInterview)
 		}
 
 		aiRoutes := api.Group("/ai")
@@ -71,6 +193,28 @@ func main() {
 			aiRoutes.POST("/scrape", handler.ScrapeJob)
 			aiRoutes.POST("/analyze/:jobId", handler.AnalyzeJob)
 			aiRoutes.POST("/follow-up/:jobId", handler.GenerateFollowUp)
+			aiRoutes.POST("/cover-letter/:jobId", handler.GenerateCoverLetter)
+			aiRoutes.POST("/rescore", handler.RescoreJobs)
+			aiRoutes.GET("/prompts", handler.GetPrompts)
+		}
+
+		api.GET("/tasks/:id", handler.GetTask)
+
+		analytics := api.Group("/analytics")
+		{
+			analytics.GET("/funnel", handler.GetFunnel)
+			analytics.GET("/latency", handler.GetLatency)
+			analytics.GET("/cv-versions", handler.GetCvVersionAnalytics)
+			analytics.GET("/salary", handler.GetSalaryAnalytics)
+		}
+
+		api.GET("/exchange-rates", handler.GetExchangeRates)
+
+		admin := api.Group("/admin")
+		admin.Use(middleware.AdminRequired())
+		{
+			admin.PUT("/exchange-rates/:currency", handler.SetExchangeRate)
+			admin.DELETE("/exchange-rates/:currency", handler.DeleteExchangeRate)
 		}
 	}
 
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/joho/godotenv"
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/task"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"github.com/myfarism/lamarr-api/pkg/queue"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	database.Connect()
+	ai.Init()
+	ai.InitPrompts()
+	ai.InitEmbedder()
+	ai.InitCache(database.DB)
+
+	queue.Connect()
+	if !queue.Enabled() {
+		log.Fatal("Worker needs REDIS_URL")
+	}
+
+	concurrency, _ := strconv.Atoi(os.Getenv("WORKER_CONCURRENCY"))
+	if concurrency < 1 {
+		concurrency = 5
+	}
+
+	srv := asynq.NewServer(queue.RedisOpt, asynq.Config{
+		Concurrency: concurrency,
+		Queues: map[string]int{
+			queue.QueueAI:          3,
+			queue.QueueMaintenance: 1,
+		},
+	})
+
+	// Ghost sweep & purge cache AI terjadwal. Unique mencegah task dobel kalau ada lebih dari satu worker.
+	sweepSpec := os.Getenv("GHOST_SWEEP_CRON")
+	if sweepSpec == "" {
+		sweepSpec = "@every 6h"
+	}
+	purgeSpec := os.Getenv("AI_CACHE_PURGE_CRON")
+	if purgeSpec == "" {
+		purgeSpec = "@every 24h"
+	}
+
+	scheduler := asynq.NewScheduler(queue.RedisOpt, nil)
+	if _, err := scheduler.Register(sweepSpec,
+		asynq.NewTask(task.TypeGhostSweep, nil),
+		asynq.Queue(queue.QueueMaintenance),
+		asynq.Unique(time.Hour),
+	); err != nil {
+		log.Fatalf("Failed to schedule ghost sweep: %v", err)
+	}
+	if _, err := scheduler.Register(purgeSpec,
+		asynq.NewTask(task.TypeCachePurge, nil),
+		asynq.Queue(queue.QueueMaintenance),
+		asynq.Unique(time.Hour),
+	); err != nil {
+		log.Fatalf("Failed to schedule ai cache purge: %v", err)
+	}
+	if err := scheduler.Start(); err != nil {
+		log.Fatalf("Failed to start scheduler: %v", err)
+	}
+	defer scheduler.Shutdown()
+
+	mux := asynq.NewServeMux()
+	task.Register(mux)
+
+	log.Printf("🛠️  Lamarr worker running (concurrency %d)", concurrency)
+	if err := srv.Run(mux); err != nil {
+		log.Fatalf("Worker stopped: %v", err)
+	}
+}
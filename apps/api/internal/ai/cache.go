@@ -0,0 +1,381 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cache content-addressed untuk output LLM dan embedding.
+// Key = hash(provider, model, versi prompt, input), jadi ganti model atau prompt otomatis miss.
+
+const (
+	defaultCacheTTL    = 7 * 24 * time.Hour
+	cacheKindEmbedding = "embedding"
+)
+
+// CacheStore — backend penyimpanan cache (Redis, Postgres, ...)
+type CacheStore interface {
+	Name() string
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, kind, key, value string, ttl time.Duration) error
+}
+
+var (
+	cacheMu    sync.RWMutex
+	cacheStore CacheStore
+	cacheTTLs  = map[string]time.Duration{}
+	cacheStats sync.Map // kind → *cacheCounter
+)
+
+// SetCacheStore ganti backend cache. nil = cache mati.
+func SetCacheStore(s CacheStore) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cacheStore = s
+}
+
+func currentCacheStore() CacheStore {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	return cacheStore
+}
+
+func cacheTTL(kind string) time.Duration {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	if ttl, ok := cacheTTLs[kind]; ok {
+		return ttl
+	}
+	if ttl, ok := cacheTTLs[""]; ok {
+		return ttl
+	}
+	return defaultCacheTTL
+}
+
+// cacheKey hash semua bagian yang mempengaruhi output
+func cacheKey(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CacheEvent satu lookup cache dalam satu request
+type CacheEvent struct {
+	Kind string `json:"kind"`
+	Hit  bool   `json:"hit"`
+}
+
+// CacheTrace kumpulkan hit/miss selama satu request, supaya bisa ditampilkan di response
+type CacheTrace struct {
+	mu     sync.Mutex
+	Hits   int          `json:"hits"`
+	Misses int          `json:"misses"`
+	Events []CacheEvent `json:"events"`
+}
+
+type cacheTraceKey struct{}
+
+// WithCacheTrace pasang CacheTrace di context. Semua lookup cache dengan context ini dicatat.
+func WithCacheTrace(ctx context.Context) (context.Context, *CacheTrace) {
+	trace := &CacheTrace{Events: []CacheEvent{}}
+	return context.WithValue(ctx, cacheTraceKey{}, trace), trace
+}
+
+// Status ringkasan untuk header X-AI-Cache: hit, miss, partial, atau none
+func (t *CacheTrace) Status() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.Hits == 0 && t.Misses == 0:
+		return "none"
+	case t.Misses == 0:
+		return "hit"
+	case t.Hits == 0:
+		return "miss"
+	default:
+		return "partial"
+	}
+}
+
+func (t *CacheTrace) record(kind string, hit bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if hit {
+		t.Hits++
+	} else {
+		t.Misses++
+	}
+	t.Events = append(t.Events, CacheEvent{Kind: kind, Hit: hit})
+}
+
+type cacheCounter struct {
+	hits, misses, errors atomic.Int64
+}
+
+// CacheCounter angka metrics per jenis cache sejak proses start
+type CacheCounter struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	Errors  int64   `json:"errors"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// CacheMetrics metrics hit/miss per jenis cache (parse, gap, embedding)
+func CacheMetrics() map[string]CacheCounter {
+	out := map[string]CacheCounter{}
+	cacheStats.Range(func(k, v any) bool {
+		c := v.(*cacheCounter)
+		counter := CacheCounter{Hits: c.hits.Load(), Misses: c.misses.Load(), Errors: c.errors.Load()}
+		if total := counter.Hits + counter.Misses; total > 0 {
+			counter.HitRate = float64(counter.Hits) / float64(total)
+		}
+		out[k.(string)] = counter
+		return true
+	})
+	return out
+}
+
+// CacheBackend nama backend cache yang aktif, "disabled" kalau mati
+func CacheBackend() string {
+	if s := currentCacheStore(); s != nil {
+		return s.Name()
+	}
+	return "disabled"
+}
+
+func counterFor(kind string) *cacheCounter {
+	v, _ := cacheStats.LoadOrStore(kind, &cacheCounter{})
+	return v.(*cacheCounter)
+}
+
+func cacheGet(ctx context.Context, kind, key string) (string, bool) {
+	s := currentCacheStore()
+	if s == nil {
+		return "", false
+	}
+
+	value, ok, err := s.Get(ctx, key)
+	if err != nil {
+		counterFor(kind).errors.Add(1)
+		log.Printf("ai cache get failed: %v", err)
+	}
+
+	if ok {
+		counterFor(kind).hits.Add(1)
+	} else {
+		counterFor(kind).misses.Add(1)
+	}
+	if trace, _ := ctx.Value(cacheTraceKey{}).(*CacheTrace); trace != nil {
+		trace.record(kind, ok)
+	}
+	return value, ok
+}
+
+func cacheSet(ctx context.Context, kind, key, value string) {
+	s := currentCacheStore()
+	if s == nil {
+		return
+	}
+
+	if err := s.Set(ctx, kind, key, value, cacheTTL(kind)); err != nil {
+		counterFor(kind).errors.Add(1)
+		log.Printf("ai cache set failed: %v", err)
+	}
+}
+
+// cachedChat cek cache dulu sebelum panggil LLM. decode dipanggil untuk hasil cache maupun hasil baru;
+// hanya output yang lolos decode (setelah repair kalau perlu) yang disimpan, jadi jawaban rusak tidak ikut ter-cache.
+// Kalau cache hit dan onToken diisi, seluruh jawaban dikirim sebagai satu token.
+func cachedChat(ctx context.Context, f Feature, promptVersion string, req ChatRequest, onToken StreamFunc, decode func(response string) error) error {
+	p, err := providerFor(f)
+	if err != nil {
+		return err
+	}
+
+	kind := string(f)
+	parts := []string{"chat", p.Name(), p.Model(), promptVersion}
+	for _, m := range req.Messages {
+		parts = append(parts, m.Content)
+	}
+	key := cacheKey(parts...)
+
+	if cached, ok := cacheGet(ctx, kind, key); ok {
+		if err := decode(cached); err == nil {
+			if onToken != nil {
+				return onToken(cached)
+			}
+			return nil
+		}
+	}
+
+	response, err := completeValidated(ctx, p, req, onToken, decode)
+	if err != nil {
+		return err
+	}
+
+	cacheSet(ctx, kind, key, response)
+	return nil
+}
+
+// RedisCache — backend utama
+type RedisCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedisCache(client redis.UniversalClient) *RedisCache {
+	return &RedisCache{client: client, prefix: "lamarr:ai-cache:"}
+}
+
+func (c *RedisCache) Name() string { return "redis" }
+
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, kind, key, value string, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// PostgresCache — fallback kalau Redis tidak dikonfigurasi atau sedang error
+type PostgresCache struct {
+	db *gorm.DB
+}
+
+func NewPostgresCache(db *gorm.DB) *PostgresCache {
+	return &PostgresCache{db: db}
+}
+
+func (c *PostgresCache) Name() string { return "postgres" }
+
+func (c *PostgresCache) Get(ctx context.Context, key string) (string, bool, error) {
+	var entry model.AICacheEntry
+	err := c.db.WithContext(ctx).Where("key = ?", key).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		c.db.WithContext(ctx).Where("key = ?", key).Delete(&model.AICacheEntry{})
+		return "", false, nil
+	}
+	return entry.Value, true, nil
+}
+
+func (c *PostgresCache) Set(ctx context.Context, kind, key, value string, ttl time.Duration) error {
+	entry := model.AICacheEntry{
+		Key:       key,
+		Kind:      kind,
+		Value:     value,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	return c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"kind", "value", "expires_at"}),
+	}).Create(&entry).Error
+}
+
+// PurgeExpired hapus entry yang sudah kedaluwarsa. Get cuma menghapus key yang dibaca,
+// jadi entry yang tidak pernah dibaca lagi dibersihkan di sini.
+func (c *PostgresCache) PurgeExpired(ctx context.Context) (int64, error) {
+	res := c.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&model.AICacheEntry{})
+	return res.RowsAffected, res.Error
+}
+
+// FallbackCache pakai primary, pindah ke secondary kalau primary error
+type FallbackCache struct {
+	primary, secondary CacheStore
+}
+
+func (c *FallbackCache) Name() string {
+	return c.primary.Name() + "+" + c.secondary.Name()
+}
+
+func (c *FallbackCache) Get(ctx context.Context, key string) (string, bool, error) {
+	value, ok, err := c.primary.Get(ctx, key)
+	if err == nil {
+		return value, ok, nil
+	}
+	log.Printf("ai cache %s failed, falling back to %s: %v", c.primary.Name(), c.secondary.Name(), err)
+	return c.secondary.Get(ctx, key)
+}
+
+func (c *FallbackCache) Set(ctx context.Context, kind, key, value string, ttl time.Duration) error {
+	if err := c.primary.Set(ctx, kind, key, value, ttl); err != nil {
+		log.Printf("ai cache %s failed, falling back to %s: %v", c.primary.Name(), c.secondary.Name(), err)
+		return c.secondary.Set(ctx, kind, key, value, ttl)
+	}
+	return nil
+}
+
+// InitCache baca config dari env:
+//
+//	AI_CACHE=off              matikan cache
+//	AI_CACHE_TTL=168h         TTL default
+//	AI_CACHE_TTL_PARSE=...    override per jenis: PARSE, GAP, EMBEDDING
+//
+// Redis dari REDIS_URL dipakai kalau ada, dengan Postgres sebagai fallback.
+func InitCache(db *gorm.DB) {
+	if v := strings.ToLower(os.Getenv("AI_CACHE")); v == "off" || v == "false" || v == "0" {
+		log.Println("AI cache disabled")
+		SetCacheStore(nil)
+		return
+	}
+
+	cacheMu.Lock()
+	for kind, env := range map[string]string{
+		"":                   "AI_CACHE_TTL",
+		string(FeatureParse): "AI_CACHE_TTL_PARSE",
+		string(FeatureGap):   "AI_CACHE_TTL_GAP",
+		cacheKindEmbedding:   "AI_CACHE_TTL_EMBEDDING",
+	} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("Invalid %s: %q", env, v)
+		}
+		cacheTTLs[kind] = ttl
+	}
+	cacheMu.Unlock()
+
+	var store CacheStore = NewPostgresCache(db)
+	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
+		opt, err := redis.ParseURL(redisURL)
+		if err != nil {
+			log.Fatalf("Failed to parse REDIS_URL: %v", err)
+		}
+		store = &FallbackCache{primary: NewRedisCache(redis.NewClient(opt)), secondary: store}
+	}
+
+	SetCacheStore(store)
+	log.Printf("✅ AI cache: %s", store.Name())
+}
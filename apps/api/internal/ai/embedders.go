@@ -0,0 +1,228 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// postJSON helper buat backend embedding yang semuanya POST JSON
+func postJSON(ctx context.Context, client *http.Client, url, apiKey string, payload any) ([]byte, error) {
+	body, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return respBody, nil
+}
+
+// ========== HuggingFace Inference API ==========
+
+type HuggingFaceEmbedder struct {
+	url    string
+	apiKey string
+	model  string
+	dim    int
+	client *http.Client
+}
+
+func NewHuggingFaceEmbedder(baseURL, apiKey, model string, dim int, client *http.Client) *HuggingFaceEmbedder {
+	if baseURL == "" {
+		baseURL = "https://api-inference.huggingface.co/models"
+	}
+	return &HuggingFaceEmbedder{
+		url:    strings.TrimSuffix(baseURL, "/") + "/" + model,
+		apiKey: apiKey,
+		model:  model,
+		dim:    dim,
+		client: client,
+	}
+}
+
+func (e *HuggingFaceEmbedder) ModelID() string { return "huggingface:" + e.model }
+func (e *HuggingFaceEmbedder) Dimension() int  { return e.dim }
+
+func (e *HuggingFaceEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	respBody, err := postJSON(ctx, e.client, e.url, e.apiKey, map[string]any{
+		"inputs": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("huggingface %w", err)
+	}
+
+	return parseFeatureExtraction(respBody)
+}
+
+// parseFeatureExtraction — HuggingFace/TEI kadang balikin []float64, kadang [][]float64
+func parseFeatureExtraction(respBody []byte) ([]float64, error) {
+	var embeddings [][]float64
+	if err := json.Unmarshal(respBody, &embeddings); err == nil {
+		if len(embeddings) == 0 {
+			return nil, fmt.Errorf("empty embedding response")
+		}
+		return embeddings[0], nil
+	}
+
+	var embedding []float64
+	if err := json.Unmarshal(respBody, &embedding); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding: %w", err)
+	}
+	if len(embedding) == 0 {
+		return nil, fmt.Errorf("empty embedding response")
+	}
+	return embedding, nil
+}
+
+// ========== OpenAI-compatible /embeddings ==========
+
+type OpenAIEmbedder struct {
+	baseURL string
+	apiKey  string
+	model   string
+	dim     int
+	client  *http.Client
+}
+
+func NewOpenAIEmbedder(baseURL, apiKey, model string, dim int, client *http.Client) *OpenAIEmbedder {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &OpenAIEmbedder{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		dim:     dim,
+		client:  client,
+	}
+}
+
+func (e *OpenAIEmbedder) ModelID() string { return "openai:" + e.model }
+func (e *OpenAIEmbedder) Dimension() int  { return e.dim }
+
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	respBody, err := postJSON(ctx, e.client, e.baseURL+"/embeddings", e.apiKey, map[string]any{
+		"model": e.model,
+		"input": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai embeddings %w", err)
+	}
+
+	var resp struct {
+		Data []struct {
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("empty embedding response")
+	}
+
+	return resp.Data[0].Embedding, nil
+}
+
+// ========== Ollama /api/embed ==========
+
+type OllamaEmbedder struct {
+	baseURL string
+	model   string
+	dim     int
+	client  *http.Client
+}
+
+func NewOllamaEmbedder(baseURL, model string, dim int, client *http.Client) *OllamaEmbedder {
+	if baseURL == "" {
+		baseURL = ollamaBaseURL
+	}
+	return &OllamaEmbedder{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		dim:     dim,
+		client:  client,
+	}
+}
+
+func (e *OllamaEmbedder) ModelID() string { return "ollama:" + e.model }
+func (e *OllamaEmbedder) Dimension() int  { return e.dim }
+
+func (e *OllamaEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	respBody, err := postJSON(ctx, e.client, e.baseURL+"/api/embed", "", map[string]any{
+		"model": e.model,
+		"input": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ollama embed %w", err)
+	}
+
+	var resp struct {
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse embedding: %w", err)
+	}
+	if len(resp.Embeddings) == 0 {
+		return nil, fmt.Errorf("empty embedding response")
+	}
+
+	return resp.Embeddings[0], nil
+}
+
+// ========== HuggingFace Text Embeddings Inference (self-host) ==========
+
+type TEIEmbedder struct {
+	baseURL string
+	model   string
+	dim     int
+	client  *http.Client
+}
+
+func NewTEIEmbedder(baseURL, model string, dim int, client *http.Client) *TEIEmbedder {
+	if baseURL == "" {
+		baseURL = "http://localhost:8081"
+	}
+	return &TEIEmbedder{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		dim:     dim,
+		client:  client,
+	}
+}
+
+func (e *TEIEmbedder) ModelID() string { return "tei:" + e.model }
+func (e *TEIEmbedder) Dimension() int  { return e.dim }
+
+func (e *TEIEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	respBody, err := postJSON(ctx, e.client, e.baseURL+"/embed", "", map[string]any{
+		"inputs": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tei %w", err)
+	}
+
+	return parseFeatureExtraction(respBody)
+}
@@ -1,61 +1,99 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"log"
 	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
+	"time"
 )
 
-type EmbeddingResponse struct {
-	Embeddings [][]float64 `json:"embeddings"`
+// Embedder ubah teks jadi vector. Dimension dan ModelID wajib dilaporkan
+// supaya vector dari model berbeda tidak pernah dibandingkan.
+type Embedder interface {
+	ModelID() string
+	Dimension() int
+	Embed(ctx context.Context, text string) ([]float64, error)
 }
 
-func GetEmbedding(ctx context.Context, text string) ([]float64, error) {
-	reqBody := map[string]interface{}{
-		"inputs": text,
-	}
+// Embedding = vector + model yang menghasilkannya
+type Embedding struct {
+	Model  string    `json:"model"`
+	Vector []float64 `json:"vector"`
+}
+
+var ErrEmbeddingMismatch = errors.New("embeddings come from different models")
+
+var (
+	embedderMu sync.RWMutex
+	embedder   Embedder
+)
 
-	body, _ := json.Marshal(reqBody)
+// SetEmbedder inject backend embedding yang dipakai GetEmbedding
+func SetEmbedder(e Embedder) {
+	embedderMu.Lock()
+	defer embedderMu.Unlock()
+	embedder = e
+}
+
+func CurrentEmbedder() (Embedder, error) {
+	embedderMu.RLock()
+	defer embedderMu.RUnlock()
+
+	if embedder == nil {
+		return nil, fmt.Errorf("no embedder configured")
+	}
+	return embedder, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST",
-		"https://api-inference.huggingface.co/models/sentence-transformers/all-MiniLM-L6-v2",
-		bytes.NewBuffer(body),
-	)
+func GetEmbedding(ctx context.Context, text string) (*Embedding, error) {
+	e, err := CurrentEmbedder()
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("HUGGINGFACE_API_KEY"))
+	// Embedder lokal lebih cepat dari lookup cache, jadi tidak di-cache
+	_, local := e.(*LocalEmbedder)
+
+	key := cacheKey(cacheKindEmbedding, e.ModelID(), text)
+	if !local {
+		if cached, ok := cacheGet(ctx, cacheKindEmbedding, key); ok {
+			var vector []float64
+			if err := json.Unmarshal([]byte(cached), &vector); err == nil && len(vector) == e.Dimension() {
+				return &Embedding{Model: e.ModelID(), Vector: vector}, nil
+			}
+		}
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	vector, err := e.Embed(ctx, text)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("huggingface error %d: %s", resp.StatusCode, string(respBody))
+	if len(vector) != e.Dimension() {
+		return nil, fmt.Errorf("%s returned %d dimensions, expected %d", e.ModelID(), len(vector), e.Dimension())
 	}
 
-	// HuggingFace returns [][]float64 for feature-extraction
-	var embeddings [][]float64
-	if err := json.Unmarshal(respBody, &embeddings); err != nil {
-		return nil, fmt.Errorf("failed to parse embedding: %w", err)
+	if body, err := json.Marshal(vector); err == nil && !local {
+		cacheSet(ctx, cacheKindEmbedding, key, string(body))
 	}
 
-	if len(embeddings) == 0 {
-		return nil, fmt.Errorf("empty embedding response")
-	}
+	return &Embedding{Model: e.ModelID(), Vector: vector}, nil
+}
 
-	return embeddings[0], nil
+// Similarity hitung cosine similarity, tapi tolak kalau model/dimensi beda
+func Similarity(a, b *Embedding) (float64, error) {
+	if a.Model != b.Model || len(a.Vector) != len(b.Vector) {
+		return 0, fmt.Errorf("%w: %s (%d) vs %s (%d)", ErrEmbeddingMismatch,
+			a.Model, len(a.Vector), b.Model, len(b.Vector))
+	}
+	return CosineSimilarity(a.Vector, b.Vector), nil
 }
 
 // CosineSimilarity hitung similarity antara dua vector
@@ -77,3 +115,103 @@ func CosineSimilarity(a, b []float64) float64 {
 
 	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
+
+type EmbedderConfig struct {
+	Provider  string // huggingface | openai | ollama | tei | local
+	BaseURL   string
+	APIKey    string
+	Model     string
+	Dimension int
+	Timeout   time.Duration
+}
+
+// Dimensi model yang umum dipakai, biar EMBEDDING_DIM tidak wajib diisi
+var knownDimensions = map[string]int{
+	"sentence-transformers/all-MiniLM-L6-v2": 384,
+	"all-minilm":                             384,
+	"BAAI/bge-small-en-v1.5":                 384,
+	"nomic-embed-text":                       768,
+	"mxbai-embed-large":                      1024,
+	"bge-m3":                                 1024,
+	"text-embedding-3-small":                 1536,
+	"text-embedding-3-large":                 3072,
+	"text-embedding-ada-002":                 1536,
+}
+
+func NewEmbedder(cfg EmbedderConfig) (Embedder, error) {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	if cfg.Model == "" {
+		switch cfg.Provider {
+		case "", "huggingface", "tei":
+			cfg.Model = "sentence-transformers/all-MiniLM-L6-v2"
+		case "openai":
+			cfg.Model = "text-embedding-3-small"
+		case "ollama":
+			cfg.Model = "nomic-embed-text"
+		}
+	}
+	if cfg.Dimension == 0 {
+		cfg.Dimension = knownDimensions[cfg.Model]
+	}
+
+	if cfg.Provider == "local" {
+		if cfg.Dimension == 0 {
+			cfg.Dimension = 384
+		}
+		return NewLocalEmbedder(cfg.Dimension), nil
+	}
+
+	if cfg.Dimension == 0 {
+		return nil, fmt.Errorf("unknown dimension for embedding model %q, set EMBEDDING_DIM", cfg.Model)
+	}
+
+	switch cfg.Provider {
+	case "", "huggingface":
+		return NewHuggingFaceEmbedder(cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.Dimension, client), nil
+	case "openai":
+		return NewOpenAIEmbedder(cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.Dimension, client), nil
+	case "ollama":
+		return NewOllamaEmbedder(cfg.BaseURL, cfg.Model, cfg.Dimension, client), nil
+	case "tei":
+		return NewTEIEmbedder(cfg.BaseURL, cfg.Model, cfg.Dimension, client), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", cfg.Provider)
+	}
+}
+
+// InitEmbedder baca config dari env:
+//
+//	EMBEDDING_PROVIDER, EMBEDDING_BASE_URL, EMBEDDING_API_KEY, EMBEDDING_MODEL, EMBEDDING_DIM
+//
+// EMBEDDING_PROVIDER=local jalan full in-process, cocok buat offline dan CI.
+func InitEmbedder() {
+	cfg := EmbedderConfig{
+		Provider: os.Getenv("EMBEDDING_PROVIDER"),
+		BaseURL:  os.Getenv("EMBEDDING_BASE_URL"),
+		APIKey:   os.Getenv("EMBEDDING_API_KEY"),
+		Model:    os.Getenv("EMBEDDING_MODEL"),
+	}
+	if v, err := strconv.Atoi(os.Getenv("EMBEDDING_DIM")); err == nil {
+		cfg.Dimension = v
+	}
+
+	if cfg.APIKey == "" {
+		switch cfg.Provider {
+		case "", "huggingface":
+			cfg.APIKey = os.Getenv("HUGGINGFACE_API_KEY")
+		case "openai":
+			cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+		}
+	}
+
+	e, err := NewEmbedder(cfg)
+	if err != nil {
+		log.Fatalf("Failed to init embedder: %v", err)
+	}
+	SetEmbedder(e)
+	log.Printf("✅ Embedder: %s (%d dims)", e.ModelID(), e.Dimension())
+}
@@ -1,79 +1,16 @@
 package ai
 
-import (
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-)
-
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
+import "net/http"
 
-type GroqRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature"`
-	MaxTokens   int       `json:"max_tokens"`
-}
-
-type GroqResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-}
-
-func Chat(ctx context.Context, systemPrompt, userMessage string) (string, error) {
-	reqBody := GroqRequest{
-		Model:       "llama-3.3-70b-versatile",
-		Temperature: 0.3,
-		MaxTokens:   2048,
-		Messages: []Message{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userMessage},
-		},
-	}
-
-	body, _ := json.Marshal(reqBody)
-
-	req, err := http.NewRequestWithContext(ctx, "POST",
-		"https://api.groq.com/openai/v1/chat/completions",
-		bytes.NewBuffer(body),
-	)
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("GROQ_API_KEY"))
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("groq error %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	var groqResp GroqResponse
-	if err := json.Unmarshal(respBody, &groqResp); err != nil {
-		return "", err
-	}
+const (
+	groqBaseURL      = "https://api.groq.com/openai/v1"
+	groqDefaultModel = "llama-3.3-70b-versatile"
+)
 
-	if len(groqResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from groq")
+// NewGroq — Groq pakai API yang OpenAI-compatible, jadi cukup bungkus OpenAICompatible
+func NewGroq(apiKey, model string, client *http.Client) *OpenAICompatible {
+	if model == "" {
+		model = groqDefaultModel
 	}
-
-	return groqResp.Choices[0].Message.Content, nil
+	return NewOpenAICompatible("groq", groqBaseURL, apiKey, model, client)
 }
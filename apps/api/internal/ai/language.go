@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bahasa output AI yang didukung (follow-up, gap analysis, cover letter)
+const (
+	LanguageEnglish    = "en"
+	LanguageIndonesian = "id"
+
+	DefaultLanguage = LanguageEnglish
+)
+
+var outputLanguages = map[string]string{
+	LanguageEnglish:    "English",
+	LanguageIndonesian: "Bahasa Indonesia",
+}
+
+// NormalizeLanguage rapikan kode bahasa output. Kosong tetap kosong (= pakai default).
+func NormalizeLanguage(lang string) (string, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return "", nil
+	}
+	if _, ok := outputLanguages[lang]; !ok {
+		return "", fmt.Errorf("invalid language: %s (use en or id)", lang)
+	}
+	return lang, nil
+}
+
+// languageName nama bahasa untuk prompt, fallback ke default
+func languageName(lang string) string {
+	if name, ok := outputLanguages[lang]; ok {
+		return name
+	}
+	return outputLanguages[DefaultLanguage]
+}
+
+// normalizePostingLanguage — bahasa lowongan hasil deteksi parser, kode ISO 639-1 (id, en, ...)
+func normalizePostingLanguage(lang string) (string, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return "", nil
+	}
+	if len(lang) != 2 || strings.Trim(lang, "abcdefghijklmnopqrstuvwxyz") != "" {
+		return "", fmt.Errorf("language must be a two-letter ISO 639-1 code, got %q", lang)
+	}
+	return lang, nil
+}
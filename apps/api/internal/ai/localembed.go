@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// LocalEmbedder — embedding pure Go tanpa network (feature hashing unigram + bigram).
+// Kualitasnya di bawah sentence-transformers, tapi deterministik dan jalan offline / di CI.
+type LocalEmbedder struct {
+	dim int
+}
+
+func NewLocalEmbedder(dim int) *LocalEmbedder {
+	return &LocalEmbedder{dim: dim}
+}
+
+func (e *LocalEmbedder) ModelID() string { return fmt.Sprintf("local:hashing-v1-%d", e.dim) }
+func (e *LocalEmbedder) Dimension() int  { return e.dim }
+
+func (e *LocalEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	vector := make([]float64, e.dim)
+
+	tokens := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+
+	for i, tok := range tokens {
+		e.add(vector, tok, 1)
+		if i > 0 {
+			e.add(vector, tokens[i-1]+" "+tok, 0.5)
+		}
+	}
+
+	// Normalisasi L2 supaya panjang teks tidak mempengaruhi similarity
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+
+	return vector, nil
+}
+
+func (e *LocalEmbedder) add(vector []float64, feature string, weight float64) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	// Bit teratas nentuin tanda, biar collision saling meniadakan
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[sum%uint64(len(vector))] += weight
+}
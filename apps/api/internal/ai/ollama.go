@@ -0,0 +1,162 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	ollamaBaseURL      = "http://localhost:11434"
+	ollamaDefaultModel = "llama3.1"
+)
+
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Format   string        `json:"format,omitempty"`
+	Options  ollamaOptions `json:"options"`
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message Message `json:"message"`
+	Done    bool    `json:"done"`
+	Error   string  `json:"error"`
+}
+
+// Ollama — backend lokal buat self-host tanpa Groq
+type Ollama struct {
+	baseURL string
+	model   string
+	client  *http.Client
+
+	noJSONMode bool
+}
+
+func NewOllama(baseURL, model string, client *http.Client) *Ollama {
+	if baseURL == "" {
+		baseURL = ollamaBaseURL
+	}
+	if model == "" {
+		model = ollamaDefaultModel
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Ollama{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  client,
+	}
+}
+
+func (p *Ollama) Name() string  { return "ollama" }
+func (p *Ollama) Model() string { return p.model }
+
+func (p *Ollama) DisableJSONMode() { p.noJSONMode = true }
+
+func (p *Ollama) Chat(ctx context.Context, req ChatRequest) (string, error) {
+	resp, err := p.post(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("ollama error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", err
+	}
+	if chatResp.Error != "" {
+		return "", fmt.Errorf("ollama error: %s", chatResp.Error)
+	}
+
+	return chatResp.Message.Content, nil
+}
+
+// ChatStream — dengan stream: true Ollama balas NDJSON, satu objek per potongan token
+func (p *Ollama) ChatStream(ctx context.Context, req ChatRequest, onToken StreamFunc) (string, error) {
+	resp, err := p.post(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ollama error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var full strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk ollamaChatResponse
+		if err := decoder.Decode(&chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			return full.String(), err
+		}
+		if chunk.Error != "" {
+			return full.String(), fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+
+		if token := chunk.Message.Content; token != "" {
+			full.WriteString(token)
+			if err := onToken(token); err != nil {
+				return full.String(), err
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+
+	return full.String(), nil
+}
+
+func (p *Ollama) post(ctx context.Context, req ChatRequest, stream bool) (*http.Response, error) {
+	reqBody := ollamaChatRequest{
+		Model:    p.model,
+		Messages: req.Messages,
+		Stream:   stream,
+		Options: ollamaOptions{
+			Temperature: req.Temperature,
+			NumPredict:  req.MaxTokens,
+		},
+	}
+	if req.JSON && !p.noJSONMode {
+		reqBody.Format = "json"
+	}
+
+	body, _ := json.Marshal(reqBody)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		p.baseURL+"/api/chat",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := p.client
+	if stream {
+		client = streamClient(client)
+	}
+	return client.Do(httpReq)
+}
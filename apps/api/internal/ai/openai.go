@@ -0,0 +1,189 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	Temperature    float64         `json:"temperature"`
+	MaxTokens      int             `json:"max_tokens"`
+	Stream         bool            `json:"stream,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// chatCompletionChunk satu event SSE dari /chat/completions dengan stream: true
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// OpenAICompatible bisa dipakai untuk endpoint apapun yang ikut format
+// /chat/completions milik OpenAI (OpenAI, Groq, vLLM, LM Studio, dll)
+type OpenAICompatible struct {
+	name    string
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+
+	noJSONMode bool
+}
+
+func NewOpenAICompatible(name, baseURL, apiKey, model string, client *http.Client) *OpenAICompatible {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OpenAICompatible{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  client,
+	}
+}
+
+func (p *OpenAICompatible) Name() string  { return p.name }
+func (p *OpenAICompatible) Model() string { return p.model }
+
+// DisableJSONMode — untuk server OpenAI-compatible yang menolak response_format
+func (p *OpenAICompatible) DisableJSONMode() { p.noJSONMode = true }
+
+func (p *OpenAICompatible) Chat(ctx context.Context, req ChatRequest) (string, error) {
+	resp, err := p.post(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("%s error %d: %s", p.name, resp.StatusCode, string(respBody))
+	}
+
+	var chatResp chatCompletionResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("no response from %s", p.name)
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+// ChatStream kirim request dengan stream: true dan baca event SSE "data: {...}" sampai [DONE].
+// Request ikut ctx, jadi kalau client putus stream ke provider ikut berhenti.
+func (p *OpenAICompatible) ChatStream(ctx context.Context, req ChatRequest, onToken StreamFunc) (string, error) {
+	resp, err := p.post(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("%s error %d: %s", p.name, resp.StatusCode, string(respBody))
+	}
+
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("invalid stream chunk from %s: %w", p.name, err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		token := chunk.Choices[0].Delta.Content
+		full.WriteString(token)
+		if err := onToken(token); err != nil {
+			return full.String(), err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return full.String(), err
+	}
+
+	return full.String(), nil
+}
+
+func (p *OpenAICompatible) post(ctx context.Context, req ChatRequest, stream bool) (*http.Response, error) {
+	reqBody := chatCompletionRequest{
+		Model:       p.model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	}
+	if req.JSON && !p.noJSONMode {
+		reqBody.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	body, _ := json.Marshal(reqBody)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		p.baseURL+"/chat/completions",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	client := p.client
+	if stream {
+		client = streamClient(client)
+	}
+	return client.Do(httpReq)
+}
@@ -2,70 +2,94 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/myfarism/lamarr-api/internal/model"
 )
 
 type ParsedJob struct {
-	Title        string `json:"title"`
-	Company      string `json:"company"`
-	Description  string `json:"description"`
-	Requirements string `json:"requirements"`
-	SalaryMin    *int   `json:"salary_min"`
-	SalaryMax    *int   `json:"salary_max"`
-	Platform     string `json:"platform"`
+	Title          string `json:"title"`
+	Company        string `json:"company"`
+	Description    string `json:"description"`
+	Requirements   string `json:"requirements"`
+	SalaryMin      *int   `json:"salary_min"`
+	SalaryMax      *int   `json:"salary_max"`
+	SalaryCurrency string `json:"salary_currency"` // ISO 4217, apa adanya dari lowongan
+	SalaryPeriod   string `json:"salary_period"`   // hour | day | week | month | year
+	SalaryType     string `json:"salary_type"`     // gross | net, kosong kalau tidak disebut
+	Platform       string `json:"platform"`
+	CompanyID      *uint  `json:"company_id,omitempty"` // diisi service kalau company cocok dengan yang sudah ada
+	Language       string `json:"language"`             // bahasa lowongan (ISO 639-1), dideteksi LLM
+	PromptVersion  string `json:"prompt_version"`
+	ParseID        uint   `json:"parse_id,omitempty"` // id ParseResult, dikirim balik saat membuat job
 }
 
 func ParseJobDescription(ctx context.Context, rawText string) (*ParsedJob, error) {
-    systemPrompt := `You are a job description parser. Extract structured information from job postings.
-The input may be raw HTML text, JSON from Next.js __NEXT_DATA__, or plain text. Find the relevant job information.
-Always respond with valid JSON only, no markdown, no explanation.
-If a field cannot be determined, use null for numbers and empty string for strings.`
-
-    userMessage := fmt.Sprintf(`Parse this content and return JSON with these exact fields:
-{
-  "title": "job title",
-  "company": "company name",
-  "description": "job description summary (max 500 chars)",
-  "requirements": "key requirements as comma-separated list",
-  "salary_min": null or number in IDR,
-  "salary_max": null or number in IDR,
-  "platform": "detected platform (linkedin/glints/jobstreet/kalibrr/other)"
-}
-
-Content:
-%s`, rawText)
-
-	response, err := Chat(ctx, systemPrompt, userMessage)
+	prompt, err := renderPrompt(promptParse, map[string]any{"Text": rawText})
 	if err != nil {
-		return nil, fmt.Errorf("groq chat failed: %w", err)
+		return nil, err
 	}
 
-	// DEBUG — log response untuk debug
-	fmt.Printf("Groq raw response: %q\n", response[:min(500, len(response))])
-	fmt.Printf("Response length: %d\n", len(response))
+	var parsed ParsedJob
+	err = cachedChat(ctx, FeatureParse, prompt.Version, prompt.jsonRequest(), nil, func(response string) error {
+		parsed = ParsedJob{}
+		return decodeJSON(response, &parsed)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	// Bersihkan response
-	response = strings.TrimSpace(response)
-	response = strings.TrimPrefix(response, "```json")
-	response = strings.TrimPrefix(response, "```")
-	response = strings.TrimSuffix(response, "```")
-	response = strings.TrimSpace(response)
+	parsed.normalize()
+	parsed.PromptVersion = prompt.Version
+	return &parsed, nil
+}
 
-	fmt.Printf("Cleaned response: %q\n", response[:min(500, len(response))])
+// Validate — schema minimal hasil parse. Field opsional tidak divalidasi di sini
+// supaya nilai aneh tidak bikin parse gagal atau memicu repair; dirapikan di normalize.
+func (j *ParsedJob) Validate() error {
+	if strings.TrimSpace(j.Title) == "" {
+		return errors.New("title must not be empty")
+	}
+	if j.SalaryMin != nil && j.SalaryMax != nil && *j.SalaryMin > *j.SalaryMax {
+		return fmt.Errorf("salary_min (%d) must not be greater than salary_max (%d)", *j.SalaryMin, *j.SalaryMax)
+	}
+	return nil
+}
 
-	var parsed ParsedJob
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("json unmarshal failed: %w (response: %s)", err, response)
+// normalize rapikan field opsional setelah decode, nilai yang tidak dikenal dikosongkan
+func (j *ParsedJob) normalize() {
+	j.Language, _ = normalizePostingLanguage(j.Language)
+
+	if j.SalaryMin != nil && *j.SalaryMin < 0 {
+		j.SalaryMin = nil
+	}
+	if j.SalaryMax != nil && *j.SalaryMax < 0 {
+		j.SalaryMax = nil
 	}
 
-	return &parsed, nil
+	j.SalaryCurrency = strings.ToUpper(strings.TrimSpace(j.SalaryCurrency))
+	if len(j.SalaryCurrency) != 3 || strings.Trim(j.SalaryCurrency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		j.SalaryCurrency = ""
+	}
+	j.SalaryPeriod = strings.ToLower(strings.TrimSpace(j.SalaryPeriod))
+	if !model.SalaryPeriods[j.SalaryPeriod] {
+		j.SalaryPeriod = ""
+	}
+	j.SalaryType = strings.ToLower(strings.TrimSpace(j.SalaryType))
+	if !model.SalaryTypes[j.SalaryType] {
+		j.SalaryType = ""
+	}
 }
 
-func min(a, b int) int {
-	if a < b { return a }
-	return b
+// stripCodeFence buang ```json ... ``` yang kadang ditambahkan model
+func stripCodeFence(response string) string {
+	response = strings.TrimSpace(response)
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+	return strings.TrimSpace(response)
 }
 
 
@@ -75,60 +99,158 @@ type GapAnalysis struct {
 	Gaps            []string `json:"gaps"`
 	Suggestion      string   `json:"suggestion"`
 	Verdict         string   `json:"verdict"`
+	Language        string   `json:"language"`
+	PromptVersion   string   `json:"prompt_version"`
 }
 
-func AnalyzeGap(ctx context.Context, cvText, jobRequirements string) (*GapAnalysis, error) {
-	systemPrompt := `You are a brutally honest career advisor. 
-Analyze the gap between a candidate's CV and job requirements.
-Always respond with valid JSON only, no markdown, no explanation.`
-
-	userMessage := fmt.Sprintf(`Analyze this CV against the job requirements.
-Return JSON with these exact fields:
-{
-  "match_percentage": number 0-100,
-  "strengths": ["strength1", "strength2", "strength3"],
-  "gaps": ["gap1", "gap2", "gap3"],
-  "suggestion": "one specific actionable suggestion",
-  "verdict": "one honest sentence about their chances"
+// Validate — match_percentage harus 0-100
+func (a *GapAnalysis) Validate() error {
+	if a.MatchPercentage < 0 || a.MatchPercentage > 100 {
+		return fmt.Errorf("match_percentage must be between 0 and 100, got %d", a.MatchPercentage)
+	}
+	return nil
 }
 
-CV:
-%s
-
-Job Requirements:
-%s`, cvText, jobRequirements)
+// AnalyzeGap — language (en/id) untuk isi strengths, gaps, suggestion dan verdict; kosong = default
+func AnalyzeGap(ctx context.Context, cvText, jobRequirements, language string) (*GapAnalysis, error) {
+	return AnalyzeGapStream(ctx, cvText, jobRequirements, language, nil)
+}
 
-	response, err := Chat(ctx, systemPrompt, userMessage)
+// AnalyzeGapStream sama seperti AnalyzeGap, tapi JSON mentah dari LLM dikirim ke onToken sambil jalan
+func AnalyzeGapStream(ctx context.Context, cvText, jobRequirements, language string, onToken StreamFunc) (*GapAnalysis, error) {
+	prompt, err := renderPrompt(promptGap, map[string]any{
+		"CvText":       cvText,
+		"Requirements": jobRequirements,
+		"LanguageName": languageName(language),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	response = strings.TrimSpace(response)
-	response = strings.TrimPrefix(response, "```json")
-	response = strings.TrimPrefix(response, "```")
-	response = strings.TrimSuffix(response, "```")
-	response = strings.TrimSpace(response)
-
 	var analysis GapAnalysis
-	if err := json.Unmarshal([]byte(response), &analysis); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	err = cachedChat(ctx, FeatureGap, prompt.Version, prompt.jsonRequest(), onToken, func(response string) error {
+		analysis = GapAnalysis{}
+		return decodeJSON(response, &analysis)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	analysis.Language = language
+	if _, ok := outputLanguages[language]; !ok {
+		analysis.Language = DefaultLanguage
+	}
+	analysis.PromptVersion = prompt.Version
 	return &analysis, nil
 }
 
-func GenerateFollowUpEmail(ctx context.Context, jobTitle, company, applicantName string, daysAgo int) (string, error) {
-	systemPrompt := `You are a professional career coach. 
-Write concise, professional follow-up emails for job applications.
-Keep it under 150 words. Be direct, not desperate.`
+// GeneratedText — output teks bebas (follow-up, cover letter) beserta versi prompt yang dipakai
+type GeneratedText struct {
+	Text          string `json:"text"`
+	PromptVersion string `json:"prompt_version"`
+}
+
+// GenerateFollowUpEmail — language en/id, kosong = default
+func GenerateFollowUpEmail(ctx context.Context, jobTitle, company, applicantName string, daysAgo int, language string) (GeneratedText, error) {
+	return GenerateFollowUpEmailStream(ctx, jobTitle, company, applicantName, daysAgo, language, nil)
+}
 
-	userMessage := fmt.Sprintf(`Write a follow-up email for:
-- Applicant: %s
-- Position: %s
-- Company: %s  
-- Applied: %d days ago
+// GenerateFollowUpEmailStream sama seperti GenerateFollowUpEmail, token dikirim ke onToken begitu datang
+func GenerateFollowUpEmailStream(ctx context.Context, jobTitle, company, applicantName string, daysAgo int, language string, onToken StreamFunc) (GeneratedText, error) {
+	prompt, err := renderPrompt(promptFollowUp, map[string]any{
+		"ApplicantName": applicantName,
+		"JobTitle":      jobTitle,
+		"Company":       company,
+		"DaysAgo":       daysAgo,
+		"LanguageName":  languageName(language),
+	})
+	if err != nil {
+		return GeneratedText{}, err
+	}
+
+	return generateText(ctx, FeatureFollowUp, prompt, onToken)
+}
+
+func generateText(ctx context.Context, f Feature, prompt RenderedPrompt, onToken StreamFunc) (GeneratedText, error) {
+	p, err := providerFor(f)
+	if err != nil {
+		return GeneratedText{}, err
+	}
+
+	text, err := complete(ctx, p, prompt.request(), onToken)
+	if err != nil {
+		return GeneratedText{}, err
+	}
+	return GeneratedText{Text: text, PromptVersion: prompt.Version}, nil
+}
+
+// Opsi cover letter. Nilai kosong diisi default oleh Normalize.
+type CoverLetterOptions struct {
+	Tone     string `json:"tone"`     // professional, friendly, enthusiastic, formal, confident
+	Language string `json:"language"` // en, id
+	Length   string `json:"length"`   // short, medium, long
+}
 
-Return only the email body, no subject line.`, applicantName, jobTitle, company, daysAgo)
+var (
+	coverLetterTones = map[string]bool{"professional": true, "friendly": true, "enthusiastic": true, "formal": true, "confident": true}
+	coverLetterWords = map[string]int{"short": 150, "medium": 250, "long": 400}
+)
+
+// Normalize isi default dan validasi opsi
+func (o *CoverLetterOptions) Normalize() error {
+	if o.Tone == "" {
+		o.Tone = "professional"
+	}
+	if o.Language == "" {
+		o.Language = DefaultLanguage
+	}
+	if o.Length == "" {
+		o.Length = "medium"
+	}
+
+	if !coverLetterTones[o.Tone] {
+		return fmt.Errorf("invalid tone: %s", o.Tone)
+	}
+	language, err := NormalizeLanguage(o.Language)
+	if err != nil {
+		return err
+	}
+	o.Language = language
+	if _, ok := coverLetterWords[o.Length]; !ok {
+		return fmt.Errorf("invalid length: %s (use short, medium or long)", o.Length)
+	}
+	return nil
+}
+
+type CoverLetterInput struct {
+	ApplicantName string
+	CvText        string
+	JobTitle      string
+	Company       string
+	Description   string
+	Requirements  string
+	Options       CoverLetterOptions
+}
+
+func GenerateCoverLetter(ctx context.Context, in CoverLetterInput) (GeneratedText, error) {
+	if err := in.Options.Normalize(); err != nil {
+		return GeneratedText{}, err
+	}
+
+	prompt, err := renderPrompt(promptCoverLetter, map[string]any{
+		"ApplicantName": in.ApplicantName,
+		"CvText":        in.CvText,
+		"JobTitle":      in.JobTitle,
+		"Company":       in.Company,
+		"Description":   in.Description,
+		"Requirements":  in.Requirements,
+		"Tone":          in.Options.Tone,
+		"LanguageName":  languageName(in.Options.Language),
+		"Words":         coverLetterWords[in.Options.Length],
+	})
+	if err != nil {
+		return GeneratedText{}, err
+	}
 
-	return Chat(ctx, systemPrompt, userMessage)
+	return generateText(ctx, FeatureCoverLetter, prompt, nil)
 }
@@ -0,0 +1,60 @@
+package ai
+
+import "testing"
+
+func TestParsedJobOptionalFieldsDoNotFailValidation(t *testing.T) {
+	var parsed ParsedJob
+	err := decodeJSON(`{"title": "Go Developer", "salary_min": 5000, "salary_max": 8000,
+		"salary_currency": "Rupiah", "salary_period": "monthly", "salary_type": "take-home",
+		"language": "Indonesian"}`, &parsed)
+	if err != nil {
+		t.Fatalf("decodeJSON: %v", err)
+	}
+
+	parsed.normalize()
+	if parsed.Language != "" || parsed.SalaryCurrency != "" || parsed.SalaryPeriod != "" || parsed.SalaryType != "" {
+		t.Errorf("unknown optional values should be dropped, got %+v", parsed)
+	}
+	if parsed.SalaryMin == nil || *parsed.SalaryMin != 5000 {
+		t.Errorf("salary_min = %v, want 5000", parsed.SalaryMin)
+	}
+}
+
+func TestParsedJobNormalize(t *testing.T) {
+	negative, high := -1, 9000
+	parsed := ParsedJob{
+		Title:          "Go Developer",
+		SalaryMin:      &negative,
+		SalaryMax:      &high,
+		SalaryCurrency: " usd ",
+		SalaryPeriod:   "Year",
+		SalaryType:     "GROSS",
+		Language:       "ID",
+	}
+
+	parsed.normalize()
+	if parsed.SalaryMin != nil {
+		t.Errorf("negative salary_min should be dropped, got %d", *parsed.SalaryMin)
+	}
+	if parsed.SalaryCurrency != "USD" || parsed.SalaryPeriod != "year" || parsed.SalaryType != "gross" || parsed.Language != "id" {
+		t.Errorf("normalized = %+v", parsed)
+	}
+}
+
+func TestParsedJobValidate(t *testing.T) {
+	low, high := 5000, 8000
+	tests := []struct {
+		name    string
+		job     ParsedJob
+		wantErr bool
+	}{
+		{"ok", ParsedJob{Title: "Go Developer", SalaryMin: &low, SalaryMax: &high}, false},
+		{"empty title", ParsedJob{Title: "  "}, true},
+		{"salary order", ParsedJob{Title: "Go Developer", SalaryMin: &high, SalaryMax: &low}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.job.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package ai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Prompt disimpan sebagai file text/template di prompts/*.tmpl. Tiap file wajib punya
+// block "version", "system" dan "user". File dengan nama sama di PROMPT_DIR menimpa versi embedded.
+
+//go:embed prompts/*.tmpl
+var embeddedPrompts embed.FS
+
+const (
+	promptParse       = "parse"
+	promptGap         = "gap"
+	promptFollowUp    = "followup"
+	promptCoverLetter = "coverletter"
+	promptRepair      = "repair"
+)
+
+type PromptTemplate struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source"` // embedded atau path file override
+	tmpl    *template.Template
+}
+
+// RenderedPrompt hasil render satu template, Version ikut disimpan bersama hasil AI
+type RenderedPrompt struct {
+	System  string
+	User    string
+	Version string
+}
+
+var (
+	promptsMu sync.RWMutex
+	prompts   = map[string]*PromptTemplate{}
+)
+
+func init() {
+	loaded, err := loadPromptFS(embeddedPrompts, "prompts", "embedded")
+	if err != nil {
+		panic(fmt.Sprintf("embedded prompts: %v", err))
+	}
+	prompts = loaded
+}
+
+// InitPrompts baca override dari PROMPT_DIR (kalau diisi) di atas prompt embedded
+func InitPrompts() {
+	dir := os.Getenv("PROMPT_DIR")
+	if dir == "" {
+		return
+	}
+	if err := LoadPrompts(dir); err != nil {
+		log.Fatalf("Failed to load prompts from %s: %v", dir, err)
+	}
+	for _, p := range Prompts() {
+		log.Printf("✅ Prompt %s: %s (%s)", p.Name, p.Version, p.Source)
+	}
+}
+
+// LoadPrompts ganti prompt embedded dengan file *.tmpl dari dir.
+// Versi override diberi suffix hash isi file, jadi prompt yang diedit tanpa naik versi tetap bisa dibedakan.
+func LoadPrompts(dir string) error {
+	overrides, err := loadPromptFS(os.DirFS(dir), ".", dir)
+	if err != nil {
+		return err
+	}
+
+	promptsMu.Lock()
+	defer promptsMu.Unlock()
+
+	merged := make(map[string]*PromptTemplate, len(prompts))
+	for name, p := range prompts {
+		if p.Source == "embedded" {
+			merged[name] = p
+		}
+	}
+	for name, p := range overrides {
+		merged[name] = p
+	}
+	prompts = merged
+	return nil
+}
+
+func loadPromptFS(fsys fs.FS, dir, source string) (map[string]*PromptTemplate, error) {
+	files, err := fs.Glob(fsys, filepath.ToSlash(filepath.Join(dir, "*.tmpl")))
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := map[string]*PromptTemplate{}
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		p, err := parsePrompt(name, string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		p.Source = source
+		if source != "embedded" {
+			p.Source = filepath.Join(source, filepath.Base(file))
+			sum := sha256.Sum256(content)
+			p.Version += "+" + hex.EncodeToString(sum[:4])
+		}
+		loaded[name] = p
+	}
+	return loaded, nil
+}
+
+func parsePrompt(name, content string) (*PromptTemplate, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range []string{"version", "system", "user"} {
+		if tmpl.Lookup(block) == nil {
+			return nil, fmt.Errorf("missing {{define %q}} block", block)
+		}
+	}
+
+	var version bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&version, "version", nil); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(version.String()) == "" {
+		return nil, fmt.Errorf("empty version")
+	}
+
+	return &PromptTemplate{
+		Name:    name,
+		Version: strings.TrimSpace(version.String()),
+		tmpl:    tmpl,
+	}, nil
+}
+
+// Prompts daftar semua prompt yang aktif beserta versinya
+func Prompts() []PromptTemplate {
+	promptsMu.RLock()
+	defer promptsMu.RUnlock()
+
+	list := make([]PromptTemplate, 0, len(prompts))
+	for _, p := range prompts {
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// renderPrompt isi template name dengan data
+func renderPrompt(name string, data any) (RenderedPrompt, error) {
+	promptsMu.RLock()
+	p, ok := prompts[name]
+	promptsMu.RUnlock()
+	if !ok {
+		return RenderedPrompt{}, fmt.Errorf("prompt %q not found", name)
+	}
+
+	var system, user bytes.Buffer
+	if err := p.tmpl.ExecuteTemplate(&system, "system", data); err != nil {
+		return RenderedPrompt{}, fmt.Errorf("render prompt %s: %w", name, err)
+	}
+	if err := p.tmpl.ExecuteTemplate(&user, "user", data); err != nil {
+		return RenderedPrompt{}, fmt.Errorf("render prompt %s: %w", name, err)
+	}
+
+	return RenderedPrompt{
+		System:  strings.TrimSpace(system.String()),
+		User:    strings.TrimSpace(user.String()),
+		Version: p.Version,
+	}, nil
+}
+
+// request bikin ChatRequest dari prompt. System kosong tidak dikirim.
+func (r RenderedPrompt) request() ChatRequest {
+	req := newChatRequest(r.System, r.User)
+	if r.System == "" {
+		req.Messages = req.Messages[1:]
+	}
+	return req
+}
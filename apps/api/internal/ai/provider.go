@@ -0,0 +1,247 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider adalah backend LLM yang dipakai semua fitur AI (parse, gap analysis, follow-up).
+type Provider interface {
+	Name() string
+	Model() string
+	Chat(ctx context.Context, req ChatRequest) (string, error)
+}
+
+type ChatRequest struct {
+	Messages    []Message
+	Temperature float64
+	MaxTokens   int
+	JSON        bool // minta output JSON (response_format / format: json) kalau provider mendukung
+}
+
+// Feature dipakai supaya tiap fitur bisa pakai provider/model sendiri
+type Feature string
+
+const (
+	FeatureDefault     Feature = "default"
+	FeatureParse       Feature = "parse"
+	FeatureGap         Feature = "gap"
+	FeatureFollowUp    Feature = "followup"
+	FeatureCoverLetter Feature = "coverletter"
+)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[Feature]Provider{}
+)
+
+// SetProvider inject provider untuk satu feature.
+// Feature tanpa provider sendiri akan fallback ke FeatureDefault.
+func SetProvider(f Feature, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[f] = p
+}
+
+func providerFor(f Feature) (Provider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	if p, ok := providers[f]; ok {
+		return p, nil
+	}
+	if p, ok := providers[FeatureDefault]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("no LLM provider configured for %q", f)
+}
+
+// Chat kirim satu system prompt + user message ke provider default
+func Chat(ctx context.Context, systemPrompt, userMessage string) (string, error) {
+	return chat(ctx, FeatureDefault, systemPrompt, userMessage)
+}
+
+func chat(ctx context.Context, f Feature, systemPrompt, userMessage string) (string, error) {
+	p, err := providerFor(f)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Chat(ctx, newChatRequest(systemPrompt, userMessage))
+}
+
+// StreamFunc dipanggil untuk tiap potongan token. Return error untuk menghentikan stream.
+type StreamFunc func(token string) error
+
+// StreamingProvider — provider yang bisa kirim token satu per satu (stream: true)
+type StreamingProvider interface {
+	Provider
+	ChatStream(ctx context.Context, req ChatRequest, onToken StreamFunc) (string, error)
+}
+
+// complete kirim req ke p. onToken nil = tidak streaming.
+// Provider tanpa streaming mengirim seluruh jawaban sebagai satu potongan.
+func complete(ctx context.Context, p Provider, req ChatRequest, onToken StreamFunc) (string, error) {
+	if onToken == nil {
+		return p.Chat(ctx, req)
+	}
+	if sp, ok := p.(StreamingProvider); ok {
+		// Groq menolak stream + response_format json_object. Saat streaming JSON tidak dipaksa,
+		// outputnya tetap lewat decodeJSON dan repair loop.
+		req.JSON = false
+		return sp.ChatStream(ctx, req, onToken)
+	}
+
+	text, err := p.Chat(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return text, onToken(text)
+}
+
+// streamClient salinan client tanpa Timeout total, karena Timeout ikut memotong body SSE
+// yang masih jalan. Stream dibatasi ResponseHeaderTimeout di transport dan ctx request.
+func streamClient(c *http.Client) *http.Client {
+	sc := *c
+	sc.Timeout = 0
+	return &sc
+}
+
+func newChatRequest(systemPrompt, userMessage string) ChatRequest {
+	return ChatRequest{
+		Temperature: 0.3,
+		MaxTokens:   2048,
+		Messages: []Message{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userMessage},
+		},
+	}
+}
+
+type ProviderConfig struct {
+	Provider string // groq | openai | ollama
+	BaseURL  string
+	APIKey   string
+	Model    string
+	Timeout  time.Duration
+
+	// NoJSONMode matikan response_format/format: json untuk endpoint yang tidak mendukung
+	NoJSONMode bool
+}
+
+// jsonModeProvider — provider yang bisa memaksa output JSON di sisi server
+type jsonModeProvider interface {
+	DisableJSONMode()
+}
+
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 60 * time.Second
+	}
+	// ResponseHeaderTimeout juga membatasi request streaming, yang tidak kena Client.Timeout
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = cfg.Timeout
+	client := &http.Client{Timeout: cfg.Timeout, Transport: transport}
+
+	var p Provider
+	switch cfg.Provider {
+	case "", "groq":
+		p = NewGroq(cfg.APIKey, cfg.Model, client)
+	case "openai":
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = "https://api.openai.com/v1"
+		}
+		if cfg.Model == "" {
+			cfg.Model = "gpt-4o-mini"
+		}
+		p = NewOpenAICompatible("openai", cfg.BaseURL, cfg.APIKey, cfg.Model, client)
+	case "ollama":
+		p = NewOllama(cfg.BaseURL, cfg.Model, client)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", cfg.Provider)
+	}
+
+	if jp, ok := p.(jsonModeProvider); ok && cfg.NoJSONMode {
+		jp.DisableJSONMode()
+	}
+	return p, nil
+}
+
+// Init baca config provider dari env:
+//
+//	LLM_PROVIDER, LLM_BASE_URL, LLM_API_KEY, LLM_MODEL, LLM_TIMEOUT (detik), LLM_JSON_MODE (off untuk mematikan)
+//
+// LLM_REPAIR_RETRIES atur berapa kali output JSON yang tidak valid dikirim balik untuk diperbaiki.
+// Tiap feature bisa override pakai prefix sendiri, misal LLM_PARSE_MODEL atau LLM_GAP_PROVIDER.
+func Init() {
+	base := providerConfigFromEnv("LLM", ProviderConfig{Provider: "groq"})
+	p, err := NewProvider(base)
+	if err != nil {
+		log.Fatalf("Failed to init LLM provider: %v", err)
+	}
+	SetProvider(FeatureDefault, p)
+	log.Printf("✅ LLM provider: %s (%s)", p.Name(), p.Model())
+
+	if v, err := strconv.Atoi(os.Getenv("LLM_REPAIR_RETRIES")); err == nil {
+		SetRepairRetries(v)
+	}
+
+	for _, f := range []Feature{FeatureParse, FeatureGap, FeatureFollowUp, FeatureCoverLetter} {
+		cfg := providerConfigFromEnv("LLM_"+strings.ToUpper(string(f)), base)
+		if cfg == base {
+			continue
+		}
+
+		p, err := NewProvider(cfg)
+		if err != nil {
+			log.Fatalf("Failed to init LLM provider for %s: %v", f, err)
+		}
+		SetProvider(f, p)
+		log.Printf("✅ LLM provider for %s: %s (%s)", f, p.Name(), p.Model())
+	}
+}
+
+func providerConfigFromEnv(prefix string, fallback ProviderConfig) ProviderConfig {
+	cfg := fallback
+
+	// Ganti provider = base URL, key dan model lama tidak berlaku lagi
+	if v := os.Getenv(prefix + "_PROVIDER"); v != "" && v != fallback.Provider {
+		cfg = ProviderConfig{Provider: v, Timeout: fallback.Timeout}
+	}
+	if v := os.Getenv(prefix + "_BASE_URL"); v != "" {
+		cfg.BaseURL = v
+	}
+	if v := os.Getenv(prefix + "_API_KEY"); v != "" {
+		cfg.APIKey = v
+	}
+	if v := os.Getenv(prefix + "_MODEL"); v != "" {
+		cfg.Model = v
+	}
+	if v, err := strconv.Atoi(os.Getenv(prefix + "_TIMEOUT")); err == nil && v > 0 {
+		cfg.Timeout = time.Duration(v) * time.Second
+	}
+	switch strings.ToLower(os.Getenv(prefix + "_JSON_MODE")) {
+	case "off", "false", "0":
+		cfg.NoJSONMode = true
+	case "on", "true", "1":
+		cfg.NoJSONMode = false
+	}
+
+	if cfg.APIKey == "" {
+		switch cfg.Provider {
+		case "", "groq":
+			cfg.APIKey = os.Getenv("GROQ_API_KEY")
+		case "openai":
+			cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+		}
+	}
+
+	return cfg
+}
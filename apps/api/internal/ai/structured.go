@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// ErrInvalidOutput — output LLM tetap tidak lolos schema setelah semua repair retry
+var ErrInvalidOutput = errors.New("invalid LLM output")
+
+// Validator dipanggil setelah JSON dari LLM berhasil di-decode
+type Validator interface {
+	Validate() error
+}
+
+const (
+	defaultRepairRetries = 2
+	maxRepairRetries     = 5
+)
+
+var repairRetries = defaultRepairRetries
+
+// SetRepairRetries atur jumlah repair prompt per request (0 = tanpa repair, maksimal 5)
+func SetRepairRetries(n int) {
+	repairRetries = max(0, min(n, maxRepairRetries))
+}
+
+// jsonRequest — request yang minta output JSON (JSON mode kalau provider mendukung)
+func (r RenderedPrompt) jsonRequest() ChatRequest {
+	req := r.request()
+	req.JSON = true
+	return req
+}
+
+// decodeJSON decode jawaban LLM ke out lalu cek schema-nya
+func decodeJSON(response string, out Validator) error {
+	if err := json.Unmarshal([]byte(extractJSON(response)), out); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	return out.Validate()
+}
+
+// extractJSON buang code fence dan teks di luar objek JSON terluar
+func extractJSON(response string) string {
+	response = stripCodeFence(response)
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return response
+	}
+	return response[start : end+1]
+}
+
+// completeValidated kirim req lalu decode hasilnya. Kalau decode gagal, jawaban rusak + alasan
+// dikirim balik sebagai repair prompt, maksimal repairRetries kali.
+// Repair tidak di-stream: token jawaban pertama sudah terkirim, hasil akhir ada di return value.
+func completeValidated(ctx context.Context, p Provider, req ChatRequest, onToken StreamFunc, decode func(response string) error) (string, error) {
+	response, err := complete(ctx, p, req, onToken)
+	if err != nil {
+		return "", fmt.Errorf("llm chat failed: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		invalid := decode(response)
+		if invalid == nil {
+			return response, nil
+		}
+		if attempt >= repairRetries {
+			return "", fmt.Errorf("%w after %d repair attempts: %v", ErrInvalidOutput, attempt, invalid)
+		}
+
+		log.Printf("LLM output from %s invalid (%v), repair attempt %d/%d", p.Name(), invalid, attempt+1, repairRetries)
+		repair, err := renderPrompt(promptRepair, map[string]any{"Error": invalid.Error()})
+		if err != nil {
+			return "", err
+		}
+		req.Messages = append(req.Messages,
+			Message{Role: "assistant", Content: response},
+			Message{Role: "user", Content: repair.User},
+		)
+
+		response, err = p.Chat(ctx, req)
+		if err != nil {
+			return "", fmt.Errorf("llm repair failed: %w", err)
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/myfarism/lamarr-api/internal/ai"
 	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/internal/task"
 	"github.com/myfarism/lamarr-api/pkg/database"
+	"github.com/myfarism/lamarr-api/pkg/queue"
 )
 
 // POST /api/ai/parse-job
@@ -22,17 +27,45 @@ func ParseJob(c *gin.Context) {
 		return
 	}
 
-	parsed, err := ai.ParseJobDescription(c.Request.Context(), input.Text)
+	if wantsAsync(c) {
+		enqueueTask(c, task.TypeParse, task.ParsePayload{UserID: currentUser(c).ID, Text: input.Text})
+		return
+	}
+
+	ctx, trace := aiContext(c)
+	parsed, err := ai.ParseJobDescription(ctx, input.Text)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse job description"})
+		c.JSON(aiErrorStatus(err), gin.H{"error": "Failed to parse job description"})
 		return
 	}
+	service.MatchParsedCompany(currentUser(c).ID, parsed)
+	service.SaveParseResult(currentUser(c).ID, parsed)
 
-	c.JSON(http.StatusOK, gin.H{"data": parsed})
+	setCacheHeader(c, trace)
+	c.JSON(http.StatusOK, gin.H{"data": parsed, "cache": trace})
+}
+
+// aiErrorStatus — output LLM yang tetap tidak valid setelah repair = 502, selain itu 500
+func aiErrorStatus(err error) int {
+	if errors.Is(err, ai.ErrInvalidOutput) {
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// requestLanguage baca override bahasa output dari ?language=en|id. Kosong = preferensi user.
+func requestLanguage(c *gin.Context) (string, bool) {
+	language, err := ai.NormalizeLanguage(c.Query("language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return language, true
 }
 
 // POST /api/ai/analyze/:jobId
-// Analyze gap antara CV user dan job requirements
+// Analyze gap antara CV user dan job requirements.
+// ?async=true jalan di worker, ?stream=true kirim output LLM lewat SSE, ?language=en|id override bahasa.
 func AnalyzeJob(c *gin.Context) {
 	user := currentUser(c)
 	jobID := c.Param("jobId")
@@ -44,7 +77,7 @@ func AnalyzeJob(c *gin.Context) {
 		return
 	}
 
-	if user.CvText == "" {
+	if cvText, _ := service.CVForJob(user, &job); cvText == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Please upload your CV text first"})
 		return
 	}
@@ -54,28 +87,37 @@ func AnalyzeJob(c *gin.Context) {
 		return
 	}
 
-	// Gap analysis pakai Groq
-	analysis, err := ai.AnalyzeGap(c.Request.Context(), user.CvText, job.Requirements)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze gap"})
+	language, ok := requestLanguage(c)
+	if !ok {
 		return
 	}
 
-	// Hitung embedding similarity juga
-	cvEmbedding, err := ai.GetEmbedding(c.Request.Context(), user.CvText)
-	if err == nil {
-		jdEmbedding, err := ai.GetEmbedding(c.Request.Context(), job.Requirements)
-		if err == nil {
-			score := ai.CosineSimilarity(cvEmbedding, jdEmbedding)
-			// Update match score di database
-			database.DB.Model(&job).Update("match_score", score)
-		}
+	if wantsAsync(c) {
+		enqueueTask(c, task.TypeAnalyze, task.AnalyzePayload{UserID: user.ID, JobID: job.ID, Language: language})
+		return
+	}
+
+	ctx, trace := aiContext(c)
+
+	if wantsStream(c) {
+		startSSE(c)
+		analysis, err := service.AnalyzeJobStream(ctx, user, &job, language, sseTokens(c))
+		finishSSE(c, gin.H{"data": analysis, "cache": trace}, err, "Failed to analyze gap")
+		return
+	}
+
+	analysis, err := service.AnalyzeJob(ctx, user, &job, language)
+	if err != nil {
+		c.JSON(aiErrorStatus(err), gin.H{"error": "Failed to analyze gap"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": analysis})
+	setCacheHeader(c, trace)
+	c.JSON(http.StatusOK, gin.H{"data": analysis, "cache": trace})
 }
 
 // POST /api/ai/follow-up/:jobId
+// ?stream=true kirim email token per token lewat SSE, ?language=en|id override bahasa
 func GenerateFollowUp(c *gin.Context) {
 	user := currentUser(c)
 	jobID := c.Param("jobId")
@@ -86,25 +128,71 @@ func GenerateFollowUp(c *gin.Context) {
 		return
 	}
 
+	override, ok := requestLanguage(c)
+	if !ok {
+		return
+	}
+	language := service.OutputLanguage(user, override)
+
 	daysAgo := int(time.Since(job.AppliedAt).Hours() / 24)
 	name := user.Name
 	if name == "" {
 		name = user.Email
 	}
 
+	if wantsStream(c) {
+		startSSE(c)
+		email, err := ai.GenerateFollowUpEmailStream(
+			c.Request.Context(),
+			job.Title,
+			job.Company,
+			name,
+			daysAgo,
+			language,
+			sseTokens(c),
+		)
+		finishSSE(c, gin.H{"data": followUpData(email, language)}, err, "Failed to generate email")
+		return
+	}
+
 	email, err := ai.GenerateFollowUpEmail(
 		c.Request.Context(),
 		job.Title,
 		job.Company,
 		name,
 		daysAgo,
+		language,
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate email"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"email": email}})
+	c.JSON(http.StatusOK, gin.H{"data": followUpData(email, language)})
+}
+
+func followUpData(email ai.GeneratedText, language string) gin.H {
+	return gin.H{"email": email.Text, "language": language, "prompt_version": email.PromptVersion}
+}
+
+// GET /api/jobs/:id/analyses
+// Riwayat gap analysis job, terbaru dulu
+func GetJobAnalyses(c *gin.Context) {
+	user := currentUser(c)
+
+	var analyses []model.JobAnalysis
+	database.DB.
+		Where("job_id = ? AND user_id = ?", c.Param("id"), user.ID).
+		Order("created_at desc").
+		Find(&analyses)
+
+	c.JSON(http.StatusOK, gin.H{"data": analyses})
+}
+
+// GET /api/ai/prompts
+// Versi template prompt yang sedang aktif
+func GetPrompts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": ai.Prompts()})
 }
 
 // PATCH /api/me/cv
@@ -120,9 +208,38 @@ func UpdateCV(c *gin.Context) {
 		return
 	}
 
-	database.DB.Model(&model.User{}).
-		Where("id = ?", user.ID).
-		Update("cv_text", input.CvText)
+	// Teks baru menimpa versi CV default (dibuat kalau belum ada)
+	if _, err := service.SaveDefaultCV(user.ID, input.CvText, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV"})
+		return
+	}
+
+	refreshCV(c, user.ID, input.CvText)
 
 	c.JSON(http.StatusOK, gin.H{"message": "CV updated"})
 }
+
+// refreshCV hitung ulang embedding CV + match score semua job, di worker kalau ada
+func refreshCV(c *gin.Context, userID uint, cvText string) {
+	if queue.Enabled() {
+		if _, err := task.Enqueue(c.Request.Context(), task.TypeRescore, task.RescorePayload{UserID: userID}); err == nil {
+			return
+		}
+	}
+	service.InBackground("cv embedding", func(ctx context.Context) error {
+		if err := service.RefreshCV(ctx, userID, cvText); err != nil {
+			return err
+		}
+		if err := service.RefreshCvVersions(ctx, userID); err != nil {
+			return err
+		}
+		return service.RescoreJobs(userID)
+	})
+}
+
+// POST /api/ai/rescore
+// Hitung ulang embedding CV dan match score semua job di worker
+func RescoreJobs(c *gin.Context) {
+	user := currentUser(c)
+	enqueueTask(c, task.TypeRescore, task.RescorePayload{UserID: user.ID})
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/ai"
+)
+
+// aiContext context request yang mencatat hit/miss cache AI.
+// Hasilnya ditampilkan lewat header X-AI-Cache dan field "cache" di response.
+func aiContext(c *gin.Context) (context.Context, *ai.CacheTrace) {
+	return ai.WithCacheTrace(c.Request.Context())
+}
+
+func setCacheHeader(c *gin.Context, trace *ai.CacheTrace) {
+	c.Header("X-AI-Cache", trace.Status())
+}
+
+// GET /metrics/ai-cache
+// Hit/miss cache LLM & embedding per jenis sejak proses start
+func GetAICacheMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"backend": ai.CacheBackend(),
+		"data":    ai.CacheMetrics(),
+	})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/service"
+)
+
+// GET /api/analytics/funnel
+// Funnel applied → screening → interview → offer dari histori timeline, plus breakdown platform & bulan
+func GetFunnel(c *gin.Context) {
+	user := currentUser(c)
+
+	report, err := service.BuildFunnelReport(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build funnel"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GET /api/analytics/latency?platform=&company=
+// Median & p90 hari sampai respon pertama, lama di tiap stage, dan hari sampai offer
+func GetLatency(c *gin.Context) {
+	user := currentUser(c)
+
+	report, err := service.BuildLatencyReport(user.ID, service.LatencyFilter{
+		Platform: c.Query("platform"),
+		Company:  c.Query("company"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build latency report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GET /api/analytics/cv-versions
+// Response rate & funnel per versi CV, untuk lihat CV mana yang paling sering dapat respon
+func GetCvVersionAnalytics(c *gin.Context) {
+	user := currentUser(c)
+
+	report, err := service.BuildCvVersionReport(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build CV version report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+func calendarURL(c *gin.Context, token string) string {
+	base := os.Getenv("PUBLIC_API_URL")
+	if base == "" {
+		scheme := "http"
+		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+			scheme = "https"
+		}
+		base = scheme + "://" + c.Request.Host
+	}
+	return strings.TrimSuffix(base, "/") + "/calendar/" + token + ".ics"
+}
+
+// GET /api/me/calendar
+// Balikin URL feed ICS. Token dibuat saat pertama kali diminta.
+func GetCalendarFeed(c *gin.Context) {
+	user := currentUser(c)
+
+	if user.CalendarToken == nil {
+		rotateCalendarToken(c, user)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"url": calendarURL(c, *user.CalendarToken)}})
+}
+
+// POST /api/me/calendar/rotate
+// Ganti token kalau URL feed bocor, URL lama langsung tidak berlaku
+func RotateCalendarFeed(c *gin.Context) {
+	rotateCalendarToken(c, currentUser(c))
+}
+
+func rotateCalendarToken(c *gin.Context, user model.User) {
+	token, err := service.NewCalendarToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar token"})
+		return
+	}
+
+	database.DB.Model(&model.User{}).Where("id = ?", user.ID).Update("calendar_token", token)
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"url": calendarURL(c, token)}})
+}
+
+// GET /calendar/:token.ics — publik, autentikasi lewat secret token di URL
+func ServeCalendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var user model.User
+	if token == "" || database.DB.Where("calendar_token = ?", token).First(&user).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+		return
+	}
+
+	ics, err := service.BuildCalendar(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build calendar"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// GET /api/companies
+// Semua company user beserta jumlah lamaran, ghost rate, dan rata-rata waktu respon
+func GetCompanies(c *gin.Context) {
+	user := currentUser(c)
+
+	stats, err := service.BuildCompanyStats(user.ID, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load companies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
+
+// GET /api/companies/:id
+func GetCompany(c *gin.Context) {
+	user := currentUser(c)
+
+	stats, err := service.BuildCompanyStats(user.ID, parseID(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load company"})
+		return
+	}
+	if len(stats) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	company := stats[0]
+	database.DB.
+		Where("user_id = ? AND company_id = ?", user.ID, company.ID).
+		Order("applied_at desc").
+		Find(&company.Jobs)
+
+	c.JSON(http.StatusOK, gin.H{"data": company})
+}
+
+// PATCH /api/companies/:id
+// Body: { "name": "Tokopedia", "aliases": ["PT Tokopedia", "Tokped"] }
+func UpdateCompany(c *gin.Context) {
+	user := currentUser(c)
+
+	var company model.Company
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&company).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	var input struct {
+		Name    string   `json:"name"`
+		Aliases []string `json:"aliases"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := service.RenameCompany(&company, input.Name, input.Aliases)
+	if errors.Is(err, service.ErrCompanyExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": company})
+}
+
+// POST /api/companies/:id/merge
+// Body: { "company_id": 7 } — semua job company 7 dipindah ke :id, lalu company 7 dihapus
+func MergeCompany(c *gin.Context) {
+	user := currentUser(c)
+
+	var input struct {
+		CompanyID uint `json:"company_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var target, source model.Company
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+	if target.ID == input.CompanyID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a company into itself"})
+		return
+	}
+	if err := database.DB.Where("id = ? AND user_id = ?", input.CompanyID, user.ID).First(&source).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company to merge not found"})
+		return
+	}
+
+	if err := service.MergeCompanies(&target, &source); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge companies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": target})
+}
+
+// companyForJob pakai company_id dari client kalau valid (misal hasil parse-job),
+// kalau tidak cari/buat berdasarkan nama
+func companyForJob(userID uint, companyID *uint, name string) *uint {
+	if companyID != nil {
+		var company model.Company
+		err := database.DB.Where("id = ? AND user_id = ?", *companyID, userID).First(&company).Error
+		if err == nil {
+			return &company.ID
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+	}
+
+	company, err := service.ResolveCompany(userID, name)
+	if err != nil || company == nil {
+		return nil
+	}
+	return &company.ID
+}
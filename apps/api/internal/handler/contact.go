@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// helper ambil contact milik user, balikin false + 404 kalau tidak ada
+func findContact(c *gin.Context, user model.User) (model.Contact, bool) {
+	var contact model.Contact
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&contact).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return contact, false
+	}
+	return contact, true
+}
+
+// GET /api/contacts?search=
+func GetContacts(c *gin.Context) {
+	user := currentUser(c)
+
+	query := database.DB.Where("user_id = ?", user.ID)
+	if search := c.Query("search"); search != "" {
+		query = query.Where(
+			"name ILIKE ? OR company ILIKE ? OR email ILIKE ?",
+			"%"+search+"%", "%"+search+"%", "%"+search+"%",
+		)
+	}
+
+	var contacts []model.Contact
+	query.Order("name asc").Find(&contacts)
+
+	c.JSON(http.StatusOK, gin.H{"data": contacts})
+}
+
+// GET /api/contacts/:id
+// Termasuk semua lamaran yang pernah melibatkan contact ini + log interaksi
+func GetContact(c *gin.Context) {
+	user := currentUser(c)
+
+	var contact model.Contact
+	err := database.DB.
+		Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+		Preload("Jobs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("applied_at desc")
+		}).
+		Preload("Interactions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("happened_at desc")
+		}).
+		First(&contact).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": contact})
+}
+
+type contactInput struct {
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Phone       string `json:"phone"`
+	LinkedInURL string `json:"linkedin_url" binding:"omitempty,url"`
+	Company     string `json:"company"`
+	Notes       string `json:"notes"`
+}
+
+// POST /api/contacts
+func CreateContact(c *gin.Context) {
+	user := currentUser(c)
+
+	var input contactInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	contact := model.Contact{
+		UserID:      user.ID,
+		Name:        input.Name,
+		Role:        input.Role,
+		Email:       input.Email,
+		Phone:       input.Phone,
+		LinkedInURL: input.LinkedInURL,
+		Company:     input.Company,
+		Notes:       input.Notes,
+	}
+	database.DB.Create(&contact)
+
+	c.JSON(http.StatusCreated, gin.H{"data": contact})
+}
+
+// PATCH /api/contacts/:id
+func UpdateContact(c *gin.Context) {
+	user := currentUser(c)
+
+	contact, ok := findContact(c, user)
+	if !ok {
+		return
+	}
+
+	var input contactInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	database.DB.Model(&contact).Updates(model.Contact{
+		Name:        input.Name,
+		Role:        input.Role,
+		Email:       input.Email,
+		Phone:       input.Phone,
+		LinkedInURL: input.LinkedInURL,
+		Company:     input.Company,
+		Notes:       input.Notes,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"data": contact})
+}
+
+// DELETE /api/contacts/:id
+func DeleteContact(c *gin.Context) {
+	user := currentUser(c)
+
+	contact, ok := findContact(c, user)
+	if !ok {
+		return
+	}
+
+	database.DB.Model(&contact).Association("Jobs").Clear()
+	database.DB.Where("contact_id = ?", contact.ID).Delete(&model.ContactInteraction{})
+	database.DB.Delete(&contact)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contact deleted"})
+}
+
+// POST /api/contacts/:id/jobs/:jobId
+func LinkContactJob(c *gin.Context) {
+	user := currentUser(c)
+
+	contact, ok := findContact(c, user)
+	if !ok {
+		return
+	}
+
+	var job model.Job
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("jobId"), user.ID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	database.DB.Model(&contact).Association("Jobs").Append(&job)
+	c.JSON(http.StatusOK, gin.H{"message": "Contact linked to job"})
+}
+
+// DELETE /api/contacts/:id/jobs/:jobId
+func UnlinkContactJob(c *gin.Context) {
+	user := currentUser(c)
+
+	contact, ok := findContact(c, user)
+	if !ok {
+		return
+	}
+
+	var job model.Job
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("jobId"), user.ID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	database.DB.Model(&contact).Association("Jobs").Delete(&job)
+	c.JSON(http.StatusOK, gin.H{"message": "Contact unlinked from job"})
+}
+
+// GET /api/contacts/:id/interactions
+func GetContactInteractions(c *gin.Context) {
+	user := currentUser(c)
+
+	contact, ok := findContact(c, user)
+	if !ok {
+		return
+	}
+
+	var interactions []model.ContactInteraction
+	database.DB.Where("contact_id = ?", contact.ID).Order("happened_at desc").Find(&interactions)
+
+	c.JSON(http.StatusOK, gin.H{"data": interactions})
+}
+
+// POST /api/contacts/:id/interactions
+// Body: { "channel": "email", "summary": "...", "job_id": 12, "happened_at": "..." }
+// Kalau job_id diisi, contact otomatis terhubung ke job tersebut
+func CreateContactInteraction(c *gin.Context) {
+	user := currentUser(c)
+
+	contact, ok := findContact(c, user)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Channel    string     `json:"channel"`
+		Summary    string     `json:"summary" binding:"required"`
+		JobID      *uint      `json:"job_id"`
+		HappenedAt *time.Time `json:"happened_at"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.JobID != nil {
+		var job model.Job
+		if err := database.DB.Where("id = ? AND user_id = ?", *input.JobID, user.ID).First(&job).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		database.DB.Model(&contact).Association("Jobs").Append(&job)
+	}
+
+	happenedAt := time.Now()
+	if input.HappenedAt != nil {
+		happenedAt = *input.HappenedAt
+	}
+
+	interaction := model.ContactInteraction{
+		ContactID:  contact.ID,
+		UserID:     user.ID,
+		JobID:      input.JobID,
+		Channel:    input.Channel,
+		Summary:    input.Summary,
+		HappenedAt: happenedAt,
+	}
+	database.DB.Create(&interaction)
+
+	c.JSON(http.StatusCreated, gin.H{"data": interaction})
+}
+
+// DELETE /api/contacts/:id/interactions/:interactionId
+func DeleteContactInteraction(c *gin.Context) {
+	user := currentUser(c)
+
+	result := database.DB.
+		Where("id = ? AND contact_id = ? AND user_id = ?", c.Param("interactionId"), c.Param("id"), user.ID).
+		Delete(&model.ContactInteraction{})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interaction not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Interaction deleted"})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// POST /api/ai/cover-letter/:jobId
+// Body (semua opsional): { "tone": "professional", "language": "id", "length": "short" }
+// Tiap panggilan membuat versi baru; opsi yang kosong mengikuti versi sebelumnya.
+func GenerateCoverLetter(c *gin.Context) {
+	user := currentUser(c)
+
+	var job model.Job
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("jobId"), user.ID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var opts ai.CoverLetterOptions
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	// Validasi awal supaya opsi salah tidak sampai ke LLM
+	check := opts
+	if err := check.Normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	letter, err := service.GenerateCoverLetter(c.Request.Context(), user, &job, opts)
+	if errors.Is(err, service.ErrNoCV) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please upload your CV text first"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate cover letter"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": letter})
+}
+
+// GET /api/jobs/:id/cover-letters
+// Semua versi cover letter job, terbaru dulu
+func GetCoverLetters(c *gin.Context) {
+	user := currentUser(c)
+
+	var letters []model.CoverLetter
+	database.DB.
+		Where("job_id = ? AND user_id = ?", c.Param("id"), user.ID).
+		Order("version desc").
+		Find(&letters)
+
+	c.JSON(http.StatusOK, gin.H{"data": letters})
+}
+
+// PATCH /api/jobs/:id/cover-letters/:letterId
+// Body: { "content": "..." } — hasil edit disimpan sebagai versi baru
+func UpdateCoverLetter(c *gin.Context) {
+	user := currentUser(c)
+
+	var base model.CoverLetter
+	err := database.DB.
+		Where("id = ? AND job_id = ? AND user_id = ?", c.Param("letterId"), c.Param("id"), user.ID).
+		First(&base).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cover letter not found"})
+		return
+	}
+
+	var input struct {
+		Content string `json:"content" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	letter := model.CoverLetter{
+		JobID:       base.JobID,
+		UserID:      user.ID,
+		Content:     input.Content,
+		Tone:        base.Tone,
+		Language:    base.Language,
+		Length:      base.Length,
+		Source:      model.CoverLetterSourceEdited,
+		CvVersionID: base.CvVersionID,
+
+		PromptVersion: base.PromptVersion, // hasil edit tetap ditelusuri ke prompt asalnya
+	}
+	if err := service.SaveCoverLetterVersion(&letter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save cover letter"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": letter})
+}
+
+// DELETE /api/jobs/:id/cover-letters/:letterId
+func DeleteCoverLetter(c *gin.Context) {
+	user := currentUser(c)
+
+	result := database.DB.
+		Where("id = ? AND job_id = ? AND user_id = ?", c.Param("letterId"), c.Param("id"), user.ID).
+		Delete(&model.CoverLetter{})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cover letter not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cover letter deleted"})
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+const maxCVSize = 10 << 20 // 10 MB
+
+// POST /api/me/cv/upload
+// Multipart form:
+//   - file: CV dalam PDF atau DOCX
+//   - label: opsional. Kalau diisi, jadi CvVersion baru; kalau tidak, menimpa versi default
+//   - make_default: "true" untuk langsung jadikan versi baru sebagai default
+//
+// Teks hasil ekstraksi jadi User.CvText (kalau default), file aslinya tetap disimpan.
+func UploadCV(c *gin.Context) {
+	user := currentUser(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxCVSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is larger than 10 MB"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxCVSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	cvText, err := service.ExtractCVText(fileHeader.Filename, data)
+	if errors.Is(err, service.ErrUnsupportedCV) || errors.Is(err, service.ErrEmptyCV) || errors.Is(err, service.ErrCVTooLarge) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Failed to extract text from CV: " + err.Error()})
+		return
+	}
+
+	cvFile := model.CvFile{
+		UserID:      user.ID,
+		FileName:    fileHeader.Filename,
+		ContentType: http.DetectContentType(data),
+		Size:        int64(len(data)),
+		Data:        data,
+	}
+	if err := database.DB.Create(&cvFile).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV file"})
+		return
+	}
+
+	var version *model.CvVersion
+	if label := strings.TrimSpace(c.PostForm("label")); label != "" {
+		version = &model.CvVersion{UserID: user.ID, Label: label, Text: cvText, CvFileID: &cvFile.ID}
+		err = database.DB.Create(version).Error
+		if err == nil && c.PostForm("make_default") == "true" {
+			err = service.SetDefaultCvVersion(version)
+		}
+	} else {
+		version, err = service.SaveDefaultCV(user.ID, cvText, &cvFile.ID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV"})
+		return
+	}
+
+	if version.IsDefault {
+		refreshCV(c, user.ID, cvText)
+	} else {
+		refreshCvVersion(*version)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"cv_text":    cvText,
+		"file":       cvFile,
+		"cv_version": version,
+	}})
+}
+
+// GET /api/me/cv/file?cv_version_id=
+// Download file CV asli: milik versi tertentu, atau yang terakhir di-upload
+func DownloadCV(c *gin.Context) {
+	user := currentUser(c)
+
+	query := database.DB.Where("user_id = ?", user.ID)
+	if versionID := c.Query("cv_version_id"); versionID != "" {
+		version, ok := findCvVersion(c, user.ID, versionID)
+		if !ok {
+			return
+		}
+		if version.CvFileID == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "CV version has no file"})
+			return
+		}
+		query = query.Where("id = ?", *version.CvFileID)
+	}
+
+	var cvFile model.CvFile
+	if err := query.Order("created_at desc").First(&cvFile).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No CV file uploaded"})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+strconv.Quote(cvFile.FileName))
+	c.Data(http.StatusOK, cvFile.ContentType, cvFile.Data)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// helper ambil CvVersion milik user, balikin false + 404 kalau tidak ada
+func findCvVersion(c *gin.Context, userID uint, id any) (model.CvVersion, bool) {
+	var version model.CvVersion
+	if err := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&version).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "CV version not found"})
+		return version, false
+	}
+	return version, true
+}
+
+// cvFileMeta preload CvFile tanpa isi file-nya
+func cvFileMeta(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "user_id", "file_name", "content_type", "size", "created_at")
+}
+
+// refreshCvVersion embed ulang satu versi CV lalu hitung ulang match score job yang memakainya
+func refreshCvVersion(version model.CvVersion) {
+	service.InBackground("cv version embedding", func(ctx context.Context) error {
+		return service.RefreshCVVersion(ctx, &version)
+	})
+}
+
+// GET /api/cv-versions
+func GetCvVersions(c *gin.Context) {
+	user := currentUser(c)
+
+	var versions []model.CvVersion
+	database.DB.Where("user_id = ?", user.ID).
+		Preload("CvFile", cvFileMeta).
+		Order("is_default desc, created_at desc").
+		Find(&versions)
+
+	c.JSON(http.StatusOK, gin.H{"data": versions})
+}
+
+// GET /api/cv-versions/:id
+func GetCvVersion(c *gin.Context) {
+	user := currentUser(c)
+
+	var version model.CvVersion
+	err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+		Preload("CvFile", cvFileMeta).
+		First(&version).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "CV version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": version})
+}
+
+// POST /api/cv-versions
+// Body: { "label": "Backend", "text": "...", "cv_file_id": 3, "is_default": false }
+func CreateCvVersion(c *gin.Context) {
+	user := currentUser(c)
+
+	var input struct {
+		Label     string `json:"label" binding:"required"`
+		Text      string `json:"text" binding:"required"`
+		CvFileID  *uint  `json:"cv_file_id"`
+		IsDefault bool   `json:"is_default"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.CvFileID != nil {
+		var count int64
+		database.DB.Model(&model.CvFile{}).Where("id = ? AND user_id = ?", *input.CvFileID, user.ID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "CV file not found"})
+			return
+		}
+	}
+
+	// CV lama di User.CvText disimpan dulu sebagai versi "Default" supaya tidak tertimpa
+	if err := service.BackfillDefaultCvVersions(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV version"})
+		return
+	}
+	current, err := service.DefaultCvVersion(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV version"})
+		return
+	}
+
+	version := model.CvVersion{
+		UserID:   user.ID,
+		Label:    input.Label,
+		Text:     input.Text,
+		CvFileID: input.CvFileID,
+	}
+	if err := database.DB.Create(&version).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV version"})
+		return
+	}
+
+	// Otomatis jadi default cuma kalau user belum punya CV sama sekali
+	if input.IsDefault || current == nil {
+		if err := service.SetDefaultCvVersion(&version); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set default CV version"})
+			return
+		}
+		refreshCV(c, user.ID, version.Text)
+	} else {
+		refreshCvVersion(version)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": version})
+}
+
+// PATCH /api/cv-versions/:id
+// Body: { "label": "...", "text": "..." }
+func UpdateCvVersion(c *gin.Context) {
+	user := currentUser(c)
+
+	version, ok := findCvVersion(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Label string `json:"label"`
+		Text  string `json:"text"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Label != "" {
+		version.Label = input.Label
+	}
+	textChanged := input.Text != "" && input.Text != version.Text
+	if textChanged {
+		version.Text = input.Text
+	}
+
+	if version.IsDefault && textChanged {
+		if err := service.SetDefaultCvVersion(&version); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV version"})
+			return
+		}
+		refreshCV(c, user.ID, version.Text)
+	} else {
+		database.DB.Save(&version)
+		if textChanged {
+			refreshCvVersion(version)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": version})
+}
+
+// POST /api/cv-versions/:id/default
+// Jadikan versi ini CV default (dipakai job baru dan job tanpa versi)
+func SetDefaultCvVersion(c *gin.Context) {
+	user := currentUser(c)
+
+	version, ok := findCvVersion(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := service.SetDefaultCvVersion(&version); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set default CV version"})
+		return
+	}
+	refreshCV(c, user.ID, version.Text)
+
+	c.JSON(http.StatusOK, gin.H{"data": version})
+}
+
+// DELETE /api/cv-versions/:id
+// Versi default tidak bisa dihapus; job yang memakai versi ini kembali ke CV default
+func DeleteCvVersion(c *gin.Context) {
+	user := currentUser(c)
+
+	version, ok := findCvVersion(c, user.ID, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if version.IsDefault {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete the default CV version, set another version as default first"})
+		return
+	}
+
+	database.DB.Model(&model.Job{}).Where("cv_version_id = ?", version.ID).Update("cv_version_id", nil)
+	database.DB.Where("cv_version_id = ?", version.ID).Delete(&model.CvVersionEmbedding{})
+	database.DB.Delete(&version)
+
+	service.InBackground("rescore", func(ctx context.Context) error {
+		return service.RescoreJobs(user.ID)
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "CV version deleted"})
+}
+
+// cvVersionForJob pakai cv_version_id dari client kalau milik user, kalau kosong pakai versi default
+func cvVersionForJob(c *gin.Context, userID uint, id *uint) (*uint, bool) {
+	if id == nil {
+		version, err := service.DefaultCvVersion(userID)
+		if err != nil || version == nil {
+			return nil, true
+		}
+		return &version.ID, true
+	}
+
+	version, ok := findCvVersion(c, userID, *id)
+	if !ok {
+		return nil, false
+	}
+	return &version.ID, true
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+const exportBatchSize = 200
+
+var exportCSVHeader = []string{
+	"id", "title", "company", "status", "platform", "url",
+	"salary_min", "salary_max", "salary_currency", "salary_period", "salary_type",
+	"salary_min_normalized", "salary_max_normalized", "match_score",
+	"applied_at", "deadline", "created_at", "updated_at",
+	"description", "requirements", "notes",
+}
+
+// GET /api/jobs/export?format=csv|json&include_timelines=true
+// Filter sama dengan GET /api/jobs (search, status, platform), tapi tanpa paging
+func ExportJobs(c *gin.Context) {
+	user := currentUser(c)
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+	includeTimelines := c.Query("include_timelines") == "true"
+
+	query := filterJobs(c, database.DB.Where("user_id = ?", user.ID))
+	if includeTimelines {
+		query = query.Preload("Timelines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("happened_at asc")
+		})
+	}
+
+	filename := fmt.Sprintf("lamarr-jobs-%s.%s", time.Now().Format("2006-01-02"), format)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == "json" {
+		c.Header("Content-Type", "application/json")
+		streamJobsJSON(c, query)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	streamJobsCSV(c, query, includeTimelines)
+}
+
+// Job diambil per batch dan langsung ditulis ke response, jadi memori tidak tergantung jumlah job
+func streamJobsJSON(c *gin.Context, query *gorm.DB) {
+	enc := json.NewEncoder(c.Writer)
+	c.Writer.WriteString("[")
+
+	first := true
+	var batch []model.Job
+	err := query.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, job := range batch {
+			if !first {
+				c.Writer.WriteString(",")
+			}
+			first = false
+			if err := enc.Encode(job); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	}).Error
+	if err != nil {
+		abortStream(c, err)
+		return
+	}
+
+	c.Writer.WriteString("]")
+}
+
+func streamJobsCSV(c *gin.Context, query *gorm.DB, includeTimelines bool) {
+	w := csv.NewWriter(c.Writer)
+
+	header := append([]string{}, exportCSVHeader...)
+	if includeTimelines {
+		header = append(header, "timeline")
+	}
+	w.Write(header)
+
+	var batch []model.Job
+	err := query.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, job := range batch {
+			row := []string{
+				strconv.FormatUint(uint64(job.ID), 10),
+				job.Title,
+				job.Company,
+				string(job.Status),
+				job.Platform,
+				job.URL,
+				formatIntPtr(job.SalaryMin),
+				formatIntPtr(job.SalaryMax),
+				job.SalaryCurrency,
+				job.SalaryPeriod,
+				job.SalaryType,
+				formatIntPtr(job.SalaryMinNormalized),
+				formatIntPtr(job.SalaryMaxNormalized),
+				formatFloatPtr(job.MatchScore),
+				job.AppliedAt.Format(time.RFC3339),
+				formatTimePtr(job.Deadline),
+				job.CreatedAt.Format(time.RFC3339),
+				job.UpdatedAt.Format(time.RFC3339),
+				job.Description,
+				job.Requirements,
+				job.Notes,
+			}
+			if includeTimelines {
+				row = append(row, formatTimeline(job.Timelines))
+			}
+			for i := range row {
+				row[i] = csvCell(row[i])
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		return w.Error()
+	}).Error
+	if err != nil {
+		abortStream(c, err)
+		return
+	}
+
+	w.Flush()
+}
+
+// abortStream — status 200 sudah terkirim, jadi error di tengah export tidak bisa jadi 500.
+// Koneksi diputus sebelum akhir response supaya client tahu file-nya tidak lengkap.
+func abortStream(c *gin.Context, err error) {
+	log.Printf("job export failed mid-stream: %v", err)
+	if conn, _, hijackErr := c.Writer.Hijack(); hijackErr == nil {
+		conn.Close()
+	}
+	c.Abort()
+}
+
+// csvNumber angka biasa (misal match_score atau selisih gaji negatif), bukan formula
+var csvNumber = regexp.MustCompile(`^[-+]?\d+(\.\d+)?$`)
+
+// csvCell cegah formula injection waktu file dibuka di spreadsheet. Angka dibiarkan supaya tetap numerik.
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) && !csvNumber.MatchString(v) {
+		return "'" + v
+	}
+	return v
+}
+
+// formatTimeline gabung timeline jadi satu sel: "2026-01-02 applied: note | ..."
+func formatTimeline(timelines []model.JobTimeline) string {
+	parts := make([]string, len(timelines))
+	for i, t := range timelines {
+		parts[i] = t.HappenedAt.Format("2006-01-02") + " " + t.Stage
+		if t.Note != "" {
+			parts[i] += ": " + t.Note
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+func formatIntPtr(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func formatFloatPtr(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
+
+func formatTimePtr(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.Format(time.RFC3339)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import "testing"
+
+func TestCSVCell(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"Tokopedia":        "Tokopedia",
+		"-0.25":            "-0.25",
+		"-1500000":         "-1500000",
+		"+62":              "+62",
+		"=SUM(A1:A9)":      "'=SUM(A1:A9)",
+		"-2+3":             "'-2+3",
+		"+cmd|' /C calc'!": "'+cmd|' /C calc'!",
+		"@SUM(1)":          "'@SUM(1)",
+		"-":                "'-",
+	}
+	for in, want := range tests {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/service"
+)
+
+const maxImportSize = 5 << 20 // 5 MB
+
+// POST /api/jobs/import
+// Multipart form:
+//   - file: CSV
+//   - source: "generic" (default) atau "linkedin" (Job Applications.csv dari LinkedIn data export)
+//   - mapping: JSON CSVMapping, wajib untuk generic. Contoh: {"columns": {"title": "Position", "company": "Company"}}
+//   - dry_run: "true" untuk cek duplikat & error tanpa menyimpan apapun
+func ImportJobs(c *gin.Context) {
+	user := currentUser(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is larger than 5 MB"})
+		return
+	}
+
+	var mapping service.CSVMapping
+	source := c.DefaultPostForm("source", "generic")
+	switch source {
+	case "linkedin":
+		mapping = service.LinkedInMapping
+	case "generic":
+		if err := json.Unmarshal([]byte(c.PostForm("mapping")), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be valid JSON"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source must be generic or linkedin"})
+		return
+	}
+
+	if err := mapping.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	candidates, issues, err := service.ParseCSV(file, mapping, source+" CSV")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	finishImport(c, user.ID, candidates, issues, c.PostForm("dry_run") == "true")
+}
+
+// POST /api/jobs/import/trello
+// Multipart form:
+//   - file: board JSON export dari Trello (Menu → Print, export, and share → Export as JSON)
+//   - mapping: JSON TrelloMapping. Contoh: {"lists": {"Applied": "applied", "HR Call": "screening"}}
+//   - dry_run: "true" untuk preview
+func ImportTrello(c *gin.Context) {
+	user := currentUser(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportSize*4 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is larger than 20 MB"})
+		return
+	}
+
+	var mapping service.TrelloMapping
+	if err := json.Unmarshal([]byte(c.PostForm("mapping")), &mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be valid JSON"})
+		return
+	}
+	if err := mapping.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	candidates, issues, err := service.ParseTrello(file, mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	finishImport(c, user.ID, candidates, issues, c.PostForm("dry_run") == "true")
+}
+
+// finishImport dipakai semua importer: simpan (atau dry run) lalu embed job baru di background
+func finishImport(c *gin.Context, userID uint, candidates []service.ImportCandidate, issues []service.ImportIssue, dryRun bool) {
+	result, err := service.CommitImport(userID, candidates, issues, dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import jobs: " + err.Error()})
+		return
+	}
+
+	if !dryRun && result.Created > 0 {
+		jobs := result.Jobs
+		service.InBackground("import embedding", func(ctx context.Context) error {
+			return service.BackfillJobEmbeddings(ctx, userID, jobs)
+		})
+		service.InBackground("import company link", func(ctx context.Context) error {
+			return service.LinkJobCompanies(jobs)
+		})
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"data": result})
+}
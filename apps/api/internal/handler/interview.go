@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// parseInterviewTime terima RFC3339 ("2026-03-01T10:00:00+07:00") atau
+// waktu lokal tanpa offset ("2026-03-01T10:00") yang dibaca di timezone interview
+func parseInterviewTime(raw string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time " + raw + ", use RFC3339 or YYYY-MM-DDTHH:MM")
+}
+
+// GET /api/jobs/:id/interviews
+func GetJobInterviews(c *gin.Context) {
+	user := currentUser(c)
+
+	var interviews []model.Interview
+	database.DB.
+		Where("job_id = ? AND user_id = ?", c.Param("id"), user.ID).
+		Order("starts_at asc").
+		Find(&interviews)
+
+	c.JSON(http.StatusOK, gin.H{"data": interviews})
+}
+
+// GET /api/interviews?from=2026-01-01&to=2026-02-01
+// Default: interview yang belum lewat
+func GetInterviews(c *gin.Context) {
+	user := currentUser(c)
+
+	query := database.DB.Preload("Job").
+		Joins("JOIN jobs ON jobs.id = interviews.job_id AND jobs.deleted_at IS NULL").
+		Where("interviews.user_id = ?", user.ID)
+
+	from := time.Now().Add(-time.Hour)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+	query = query.Where("interviews.ends_at >= ?", from)
+
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("interviews.starts_at < ?", t)
+	}
+
+	var interviews []model.Interview
+	query.Order("interviews.starts_at asc").Find(&interviews)
+
+	c.JSON(http.StatusOK, gin.H{"data": interviews})
+}
+
+// POST /api/jobs/:id/interviews
+func CreateInterview(c *gin.Context) {
+	user := currentUser(c)
+
+	var job model.Job
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var input struct {
+		RoundName    string   `json:"round_name" binding:"required"`
+		StartsAt     string   `json:"starts_at" binding:"required"`
+		EndsAt       string   `json:"ends_at"`
+		Timezone     string   `json:"timezone"`
+		Location     string   `json:"location"`
+		MeetingLink  string   `json:"meeting_link"`
+		Interviewers []string `json:"interviewers"`
+		Notes        string   `json:"notes"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Timezone == "" {
+		input.Timezone = model.DefaultTimezone
+	}
+	loc, err := time.LoadLocation(input.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone: " + input.Timezone})
+		return
+	}
+
+	startsAt, err := parseInterviewTime(input.StartsAt, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "starts_at: " + err.Error()})
+		return
+	}
+
+	// Default durasi 1 jam
+	endsAt := startsAt.Add(time.Hour)
+	if input.EndsAt != "" {
+		if endsAt, err = parseInterviewTime(input.EndsAt, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at: " + err.Error()})
+			return
+		}
+	}
+	if !endsAt.After(startsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	interview := model.Interview{
+		JobID:        job.ID,
+		UserID:       user.ID,
+		RoundName:    input.RoundName,
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+		Timezone:     input.Timezone,
+		Location:     input.Location,
+		MeetingLink:  input.MeetingLink,
+		Interviewers: input.Interviewers,
+		Notes:        input.Notes,
+	}
+	database.DB.Create(&interview)
+
+	c.JSON(http.StatusCreated, gin.H{"data": interview})
+}
+
+// PATCH /api/interviews/:id
+func UpdateInterview(c *gin.Context) {
+	user := currentUser(c)
+
+	var interview model.Interview
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&interview).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+
+	var input struct {
+		RoundName    *string   `json:"round_name"`
+		StartsAt     *string   `json:"starts_at"`
+		EndsAt       *string   `json:"ends_at"`
+		Timezone     *string   `json:"timezone"`
+		Location     *string   `json:"location"`
+		MeetingLink  *string   `json:"meeting_link"`
+		Interviewers *[]string `json:"interviewers"`
+		Notes        *string   `json:"notes"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if input.Timezone != nil {
+		interview.Timezone = *input.Timezone
+	}
+	loc, err := time.LoadLocation(interview.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone: " + interview.Timezone})
+		return
+	}
+
+	if input.StartsAt != nil {
+		startsAt, err := parseInterviewTime(*input.StartsAt, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "starts_at: " + err.Error()})
+			return
+		}
+		// Geser jam selesai bareng supaya durasi tetap
+		if input.EndsAt == nil {
+			interview.EndsAt = startsAt.Add(interview.EndsAt.Sub(interview.StartsAt))
+		}
+		interview.StartsAt = startsAt
+	}
+	if input.EndsAt != nil {
+		endsAt, err := parseInterviewTime(*input.EndsAt, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at: " + err.Error()})
+			return
+		}
+		interview.EndsAt = endsAt
+	}
+	if !interview.EndsAt.After(interview.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	if input.RoundName != nil {
+		interview.RoundName = *input.RoundName
+	}
+	if input.Location != nil {
+		interview.Location = *input.Location
+	}
+	if input.MeetingLink != nil {
+		interview.MeetingLink = *input.MeetingLink
+	}
+	if input.Interviewers != nil {
+		interview.Interviewers = *input.Interviewers
+	}
+	if input.Notes != nil {
+		interview.Notes = *input.Notes
+	}
+
+	database.DB.Save(&interview)
+	c.JSON(http.StatusOK, gin.H{"data": interview})
+}
+
+// DELETE /api/interviews/:id
+func DeleteInterview(c *gin.Context) {
+	user := currentUser(c)
+
+	result := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).Delete(&model.Interview{})
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Interview deleted"})
+}
@@ -1,13 +1,17 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
 	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
 )
 
 // helper ambil user dari context
@@ -16,44 +20,25 @@ func currentUser(c *gin.Context) model.User {
 	return user.(model.User)
 }
 
-// GET /api/jobs
+// GET /api/jobs?page=&limit=&sort=salary&min_salary=
 func GetJobs(c *gin.Context) {
     user := currentUser(c)
 
     page, _  := strconv.Atoi(c.DefaultQuery("page", "1"))
     limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10")) // ← ubah default ke 10
-    search   := c.Query("search")
-    status   := c.Query("status")   // ← tambah
-    platform := c.Query("platform") // ← tambah
 
     if page < 1  { page = 1 }
     if limit > 100 { limit = 100 }
     offset := (page - 1) * limit
 
-    query := database.DB.Where("user_id = ?", user.ID)
-
-    if search != "" {
-        query = query.Where(
-            "title ILIKE ? OR company ILIKE ?",
-            "%"+search+"%", "%"+search+"%",
-        )
-    }
-
-    // ← tambah filter status
-    if status != "" {
-        query = query.Where("status = ?", status)
-    }
-
-    // ← tambah filter platform
-    if platform != "" && platform != "all" {
-        query = query.Where("platform = ?", platform)
-    }
+    query := filterJobs(c, database.DB.Where("user_id = ?", user.ID))
 
     var total int64
     query.Model(&model.Job{}).Count(&total)
 
     var jobs []model.Job
-    query.Order("created_at desc").
+    query.Preload("Tags").
+        Order(jobsOrder(c)).
         Limit(limit).
         Offset(offset).
         Find(&jobs)
@@ -71,6 +56,87 @@ func GetJobs(c *gin.Context) {
 
 
 
+// jobsOrder — ?sort=salary urutkan dari gaji normalized tertinggi (base currency, per bulan)
+func jobsOrder(c *gin.Context) string {
+	if c.Query("sort") == "salary" {
+		return "COALESCE(salary_max_normalized, salary_min_normalized) DESC NULLS LAST, created_at desc"
+	}
+	return "created_at desc"
+}
+
+// filterJobs filter search/status/platform/tag/min_salary yang dipakai GetJobs dan ExportJobs
+func filterJobs(c *gin.Context, query *gorm.DB) *gorm.DB {
+	search := c.Query("search")
+	status := c.Query("status")
+	platform := c.Query("platform")
+
+	if search != "" {
+		query = query.Where(
+			"title ILIKE ? OR company ILIKE ?",
+			"%"+search+"%", "%"+search+"%",
+		)
+	}
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if platform != "" && platform != "all" {
+		query = query.Where("platform = ?", platform)
+	}
+
+	// min_salary dalam base currency per bulan, dibandingkan ke batas atas gaji job
+	if minSalary, err := strconv.Atoi(c.Query("min_salary")); err == nil && minSalary > 0 {
+		query = query.Where("COALESCE(salary_max_normalized, salary_min_normalized) >= ?", minSalary)
+	}
+
+	return filterTags(c, query)
+}
+
+// GET /api/jobs/semantic-search?q=
+// Cari job pakai kemiripan makna, bukan cuma ILIKE title/company
+func SemanticSearchJobs(c *gin.Context) {
+	user := currentUser(c)
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	// Job lama yang belum punya embedding di-embed di background,
+	// jadi hasilnya lengkap di pencarian berikutnya
+	missing, err := service.MissingJobEmbeddings(user.ID)
+	if err == nil && len(missing) > 0 {
+		service.InBackground("embedding backfill", func(ctx context.Context) error {
+			return service.BackfillJobEmbeddings(ctx, user.ID, missing)
+		})
+	}
+
+	results, err := service.SemanticSearch(c.Request.Context(), user.ID, q, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": results,
+		"meta": gin.H{
+			"query":   q,
+			"limit":   limit,
+			"pending": len(missing),
+		},
+	})
+}
+
 // GET /api/jobs/:id
 func GetJob(c *gin.Context) {
 	user := currentUser(c)
@@ -80,6 +146,11 @@ func GetJob(c *gin.Context) {
 	result := database.DB.
 		Where("id = ? AND user_id = ?", id, user.ID).
 		Preload("Timelines").
+		Preload("Interviews", func(db *gorm.DB) *gorm.DB {
+			return db.Order("starts_at asc")
+		}).
+		Preload("Contacts").
+		Preload("Tags").
 		First(&job)
 
 	if result.Error != nil {
@@ -105,6 +176,15 @@ func CreateJob(c *gin.Context) {
 		SalaryMax    *int       `json:"salary_max"`
 		Notes        string     `json:"notes"`
 		Deadline     *time.Time `json:"deadline"`
+		CompanyID    *uint      `json:"company_id"`
+		CvVersionID  *uint      `json:"cv_version_id"`
+
+		SalaryCurrency string `json:"salary_currency"` // default base currency
+		SalaryPeriod   string `json:"salary_period"`   // default month
+		SalaryType     string `json:"salary_type"`
+
+		ParseID  *uint  `json:"parse_id"`                           // parse_id dari /ai/parse-job atau /ai/scrape
+		Language string `json:"language" binding:"omitempty,len=2"` // bahasa lowongan, dari hasil parse
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -112,10 +192,28 @@ func CreateJob(c *gin.Context) {
 		return
 	}
 
+	// Catat versi CV yang dikirim; default kalau tidak dipilih
+	cvVersionID, ok := cvVersionForJob(c, user.ID, input.CvVersionID)
+	if !ok {
+		return
+	}
+
+	// Versi prompt diambil dari hasil parse yang tersimpan, bukan dari client
+	var parsePromptVersion string
+	if input.ParseID != nil {
+		version, err := service.ParsePromptVersion(user.ID, *input.ParseID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parse result not found"})
+			return
+		}
+		parsePromptVersion = version
+	}
+
 	job := model.Job{
 		UserID:       user.ID,
 		Title:        input.Title,
 		Company:      input.Company,
+		CompanyID:    companyForJob(user.ID, input.CompanyID, input.Company),
 		URL:          input.URL,
 		Platform:     input.Platform,
 		Description:  input.Description,
@@ -124,8 +222,24 @@ func CreateJob(c *gin.Context) {
 		SalaryMax:    input.SalaryMax,
 		Notes:        input.Notes,
 		Deadline:     input.Deadline,
+		CvVersionID:  cvVersionID,
 		Status:       model.StatusApplied,
 		AppliedAt:    time.Now(),
+
+		ParsePromptVersion: parsePromptVersion,
+		Language:           strings.ToLower(strings.TrimSpace(input.Language)),
+
+		SalaryCurrency: input.SalaryCurrency,
+		SalaryPeriod:   input.SalaryPeriod,
+		SalaryType:     input.SalaryType,
+	}
+	if err := service.NormalizeSalaryFields(&job); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := service.NormalizeJobSalary(&job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to normalize salary"})
+		return
 	}
 
 	database.DB.Create(&job)
@@ -138,17 +252,22 @@ func CreateJob(c *gin.Context) {
 		HappenedAt: time.Now(),
 	})
 
+	refreshJobEmbeddings(job)
+
 	c.JSON(http.StatusCreated, gin.H{"data": job})
 }
 
 // PATCH /api/jobs/:id/status
+// Body: { "status": "interview", "note": "...", "override": false }
+// Perpindahan di luar alur normal (misal offer → applied) butuh "override": true dan dicatat di timeline
 func UpdateJobStatus(c *gin.Context) {
 	user := currentUser(c)
 	id := c.Param("id")
 
 	var input struct {
-		Status model.JobStatus `json:"status" binding:"required"`
-		Note   string          `json:"note"`
+		Status   model.JobStatus `json:"status" binding:"required"`
+		Note     string          `json:"note"`
+		Override bool            `json:"override"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -156,6 +275,14 @@ func UpdateJobStatus(c *gin.Context) {
 		return
 	}
 
+	if !input.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid status: " + string(input.Status),
+			"allowed": model.AllStatuses,
+		})
+		return
+	}
+
 	var job model.Job
 	result := database.DB.
 		Where("id = ? AND user_id = ?", id, user.ID).
@@ -167,12 +294,32 @@ func UpdateJobStatus(c *gin.Context) {
 	}
 
 	oldStatus := job.Status
+	if oldStatus == input.Status {
+		c.JSON(http.StatusOK, gin.H{"data": job})
+		return
+	}
+
+	overridden := !oldStatus.CanTransitionTo(input.Status)
+	if overridden && !input.Override {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Cannot move job from " + string(oldStatus) + " to " + string(input.Status) + " without override",
+			"allowed": oldStatus.NextStatuses(),
+		})
+		return
+	}
+
 	job.Status = input.Status
 	database.DB.Save(&job)
 
 	// Catat perubahan status di timeline
 	note := input.Note
-	if note == "" {
+	if overridden {
+		override := "[override] Status " + oldStatus.OverrideLabel(input.Status) + " from " + string(oldStatus) + " to " + string(input.Status)
+		if note != "" {
+			override += ": " + note
+		}
+		note = override
+	} else if note == "" {
 		note = "Status changed from " + string(oldStatus) + " to " + string(input.Status)
 	}
 
@@ -212,14 +359,83 @@ func UpdateJob(c *gin.Context) {
 		SalaryMax    *int       `json:"salary_max"`
 		Notes        string     `json:"notes"`
 		Deadline     *time.Time `json:"deadline"`
+		CvVersionID  *uint      `json:"cv_version_id"`
+		Language     string     `json:"language" binding:"omitempty,len=2"`
+
+		SalaryCurrency string `json:"salary_currency"`
+		SalaryPeriod   string `json:"salary_period"`
+		SalaryType     string `json:"salary_type"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	input.Language = strings.ToLower(strings.TrimSpace(input.Language))
+
+	if input.CvVersionID != nil {
+		if _, ok := findCvVersion(c, user.ID, *input.CvVersionID); !ok {
+			return
+		}
+	}
+
+	// Validasi gaji dengan nilai lama sebagai dasar, lalu hitung ulang versi normalized
+	salaryChanged := input.SalaryMin != nil || input.SalaryMax != nil ||
+		input.SalaryCurrency != "" || input.SalaryPeriod != "" || input.SalaryType != ""
+	salary := job
+	if salaryChanged {
+		if input.SalaryMin != nil {
+			salary.SalaryMin = input.SalaryMin
+		}
+		if input.SalaryMax != nil {
+			salary.SalaryMax = input.SalaryMax
+		}
+		if input.SalaryCurrency != "" {
+			salary.SalaryCurrency = input.SalaryCurrency
+		}
+		if input.SalaryPeriod != "" {
+			salary.SalaryPeriod = input.SalaryPeriod
+		}
+		if input.SalaryType != "" {
+			salary.SalaryType = input.SalaryType
+		}
+		if err := service.NormalizeSalaryFields(&salary); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Dihitung sebelum update supaya gagal baca kurs tidak meninggalkan gaji setengah tersimpan
+		if err := service.NormalizeJobSalary(&salary); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to normalize salary"})
+			return
+		}
+		input.SalaryCurrency, input.SalaryPeriod, input.SalaryType = salary.SalaryCurrency, salary.SalaryPeriod, salary.SalaryType
+	}
 
 	database.DB.Model(&job).Updates(input)
+
+	if salaryChanged {
+		job.SalaryMin, job.SalaryMax = salary.SalaryMin, salary.SalaryMax
+		job.SalaryCurrency, job.SalaryPeriod, job.SalaryType = salary.SalaryCurrency, salary.SalaryPeriod, salary.SalaryType
+		job.SalaryMinNormalized, job.SalaryMaxNormalized = salary.SalaryMinNormalized, salary.SalaryMaxNormalized
+		database.DB.Model(&job).Updates(map[string]interface{}{
+			"salary_min_normalized": job.SalaryMinNormalized,
+			"salary_max_normalized": job.SalaryMaxNormalized,
+		})
+	}
+
+	if input.Company != "" {
+		job.Company = input.Company
+		service.LinkJobCompany(&job)
+	}
+
+	if input.Description != "" || input.Requirements != "" {
+		refreshJobEmbeddings(job)
+	} else if input.CvVersionID != nil {
+		service.InBackground("rescore", func(ctx context.Context) error {
+			return service.RescoreJobs(user.ID)
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": job})
 }
 
@@ -238,6 +454,12 @@ func DeleteJob(c *gin.Context) {
 
     // Hapus timeline dulu (foreign key constraint)
     database.DB.Where("job_id = ?", job.ID).Delete(&model.JobTimeline{})
+    database.DB.Where("job_id = ?", job.ID).Delete(&model.JobEmbedding{})
+    database.DB.Where("job_id = ?", job.ID).Delete(&model.Interview{})
+    database.DB.Where("job_id = ?", job.ID).Delete(&model.CoverLetter{})
+    database.DB.Where("job_id = ?", job.ID).Delete(&model.JobAnalysis{})
+    database.DB.Model(&job).Association("Contacts").Clear()
+    database.DB.Model(&job).Association("Tags").Clear()
 
     // Baru hapus job-nya
     database.DB.Delete(&job)
@@ -245,7 +467,7 @@ func DeleteJob(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"message": "Job deleted"})
 }
 
-// GET /api/jobs/stats
+// GET /api/jobs/stats?tag=&tag_mode=
 func GetStats(c *gin.Context) {
 	user := currentUser(c)
 
@@ -254,14 +476,14 @@ func GetStats(c *gin.Context) {
 		Count  int    `json:"count"`
 	}
 
-	database.DB.Model(&model.Job{}).
+	filterTags(c, database.DB.Model(&model.Job{})).
 		Select("status, count(*) as count").
 		Where("user_id = ?", user.ID).
 		Group("status").
 		Scan(&stats)
 
 	var total int64
-	database.DB.Model(&model.Job{}).
+	filterTags(c, database.DB.Model(&model.Job{})).
 		Where("user_id = ?", user.ID).
 		Count(&total)
 
@@ -271,6 +493,18 @@ func GetStats(c *gin.Context) {
 	})
 }
 
+// refreshJobEmbeddings simpan embedding description/requirements di background
+func refreshJobEmbeddings(job model.Job) {
+	service.InBackground("job embedding", func(ctx context.Context) error {
+		// Reload biar pakai teks terbaru setelah Updates
+		var fresh model.Job
+		if err := database.DB.First(&fresh, job.ID).Error; err != nil {
+			return err
+		}
+		return service.RefreshJob(ctx, &fresh)
+	})
+}
+
 // GET /api/jobs/stats — helper buat convert string id
 func parseID(s string) uint {
 	id, _ := strconv.ParseUint(s, 10, 32)
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// GET /api/exchange-rates
+// Kurs ke base currency yang dipakai normalisasi gaji
+func GetExchangeRates(c *gin.Context) {
+	var rates []model.ExchangeRate
+	database.DB.Order("currency asc").Find(&rates)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": rates,
+		"meta": gin.H{"base_currency": service.BaseCurrency()},
+	})
+}
+
+// PUT /api/admin/exchange-rates/:currency
+// Body: { "rate": 16250 } — 1 unit currency = rate unit base currency
+func SetExchangeRate(c *gin.Context) {
+	user := currentUser(c)
+
+	var input struct {
+		Rate float64 `json:"rate" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	currency, err := service.NormalizeCurrency(c.Param("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if currency == service.BaseCurrency() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Base currency rate is always 1"})
+		return
+	}
+
+	rate, err := service.SetExchangeRate(currency, input.Rate, user.Email)
+	if errors.Is(err, service.ErrInvalidRate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save exchange rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rate})
+}
+
+// DELETE /api/admin/exchange-rates/:currency
+func DeleteExchangeRate(c *gin.Context) {
+	if err := service.DeleteExchangeRate(c.Param("currency")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete exchange rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rate deleted"})
+}
+
+// GET /api/analytics/salary
+// Statistik gaji per bulan dalam base currency, plus breakdown status/platform/currency asli
+func GetSalaryAnalytics(c *gin.Context) {
+	user := currentUser(c)
+
+	report, err := service.BuildSalaryReport(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build salary report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
-	"github.com/myfarism/lamarr-api/internal/ai"
 	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/internal/task"
 )
 
 // POST /api/scrape
@@ -18,33 +20,25 @@ func ScrapeJob(c *gin.Context) {
         return
     }
 
-    scraped, err := service.ScrapeJob(c.Request.Context(), input.URL)
-    if err != nil {
-        c.JSON(500, gin.H{"error": err.Error()})
+    if wantsAsync(c) {
+        enqueueTask(c, task.TypeScrape, task.ScrapePayload{UserID: currentUser(c).ID, URL: input.URL})
         return
     }
 
-    // Kirim RawText (bukan Description) supaya AI dapat data penuh
-    sourceText := scraped.RawText
-    if sourceText == "" {
+    ctx, trace := aiContext(c)
+    parsed, err := service.ScrapeAndParse(ctx, input.URL)
+    if errors.Is(err, service.ErrNoContent) {
         c.JSON(500, gin.H{"error": "No content scraped from URL"})
         return
     }
-
-    parsed, err := ai.ParseJobDescription(c.Request.Context(), sourceText)
     if err != nil {
-        c.JSON(500, gin.H{"error": "AI parsing failed: " + err.Error()})
+        c.JSON(aiErrorStatus(err), gin.H{"error": err.Error()})
         return
     }
+    service.MatchParsedCompany(currentUser(c).ID, parsed)
+    service.SaveParseResult(currentUser(c).ID, parsed)
 
-    // Override dengan data scraper jika lebih reliable
-    if scraped.Platform != "" {
-        parsed.Platform = scraped.Platform
-    }
-    if scraped.Title != "" {
-        parsed.Title = scraped.Title
-    }
-
-    c.JSON(200, gin.H{"data": parsed})
+    setCacheHeader(c, trace)
+    c.JSON(200, gin.H{"data": parsed, "cache": trace})
 }
 
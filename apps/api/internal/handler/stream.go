@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/ai"
+)
+
+// wantsStream — endpoint AI kirim token lewat Server-Sent Events kalau dipanggil dengan
+// ?stream=true atau header Accept: text/event-stream
+func wantsStream(c *gin.Context) bool {
+	v := c.Query("stream")
+	return v == "true" || v == "1" || strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// startSSE kirim header SSE. Setelah ini response tidak bisa balik ke JSON biasa,
+// jadi semua validasi harus selesai sebelum dipanggil.
+//
+// Event yang dikirim:
+//   - token: { "text": "..." } tiap potongan output LLM
+//   - done:  { "data": ... } hasil akhir, sama dengan response versi non-stream
+//   - error: { "error": "..." }
+func startSSE(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // matikan buffering nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
+
+// sseTokens kirim tiap token sebagai event "token". Kalau client sudah putus, error dari
+// context menghentikan stream — request ke provider LLM juga ikut batal karena pakai context yang sama.
+func sseTokens(c *gin.Context) ai.StreamFunc {
+	return func(token string) error {
+		if err := c.Request.Context().Err(); err != nil {
+			return err
+		}
+		sendSSE(c, "token", gin.H{"text": token})
+		return nil
+	}
+}
+
+func sendSSE(c *gin.Context, event string, data any) {
+	c.SSEvent(event, data)
+	c.Writer.Flush()
+}
+
+// finishSSE kirim event done/error di akhir stream. Tidak kirim apa-apa kalau client sudah putus.
+func finishSSE(c *gin.Context, done gin.H, err error, message string) {
+	if c.Request.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		sendSSE(c, "error", gin.H{"error": message})
+		return
+	}
+	sendSSE(c, "done", done)
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// filterTags filter ?tag=remote&tag=dream job&tag_mode=and|or (default and).
+// Dipakai GetJobs, ExportJobs, dan GetStats.
+func filterTags(c *gin.Context, query *gorm.DB) *gorm.DB {
+	var names []string
+	for _, value := range c.QueryArray("tag") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return query
+	}
+
+	sub := database.DB.Table("job_tags").
+		Select("job_tags.job_id").
+		Joins("JOIN tags ON tags.id = job_tags.tag_id").
+		Where("LOWER(tags.name) IN ?", names)
+
+	if c.DefaultQuery("tag_mode", "and") != "or" {
+		sub = sub.Group("job_tags.job_id").
+			Having("COUNT(DISTINCT LOWER(tags.name)) = ?", countDistinct(names))
+	}
+
+	return query.Where("id IN (?)", sub)
+}
+
+func countDistinct(values []string) int {
+	seen := map[string]bool{}
+	for _, v := range values {
+		seen[v] = true
+	}
+	return len(seen)
+}
+
+// findOrCreateTags ambil tag user berdasarkan nama (case-insensitive), buat yang belum ada
+func findOrCreateTags(userID uint, names []string) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var tag model.Tag
+		err := database.DB.Where("user_id = ? AND LOWER(name) = ?", userID, key).First(&tag).Error
+		if err != nil {
+			tag = model.Tag{UserID: userID, Name: name}
+			if err := database.DB.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// GET /api/tags
+// Semua tag user + jumlah job yang memakainya
+func GetTags(c *gin.Context) {
+	user := currentUser(c)
+
+	var tags []struct {
+		model.Tag
+		JobCount int `json:"job_count"`
+	}
+
+	database.DB.Model(&model.Tag{}).
+		Select("tags.*, COUNT(jobs.id) as job_count").
+		Joins("LEFT JOIN job_tags ON job_tags.tag_id = tags.id").
+		Joins("LEFT JOIN jobs ON jobs.id = job_tags.job_id AND jobs.deleted_at IS NULL").
+		Where("tags.user_id = ?", user.ID).
+		Group("tags.id").
+		Order("tags.name asc").
+		Scan(&tags)
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// POST /api/tags
+// Body: { "name": "remote", "color": "#22c55e" }
+func CreateTag(c *gin.Context) {
+	user := currentUser(c)
+
+	var input struct {
+		Name  string `json:"name" binding:"required"`
+		Color string `json:"color"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := strings.TrimSpace(input.Name)
+	var count int64
+	database.DB.Model(&model.Tag{}).
+		Where("user_id = ? AND LOWER(name) = LOWER(?)", user.ID, name).
+		Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Tag already exists"})
+		return
+	}
+
+	tag := model.Tag{UserID: user.ID, Name: name, Color: input.Color}
+	database.DB.Create(&tag)
+
+	c.JSON(http.StatusCreated, gin.H{"data": tag})
+}
+
+// PATCH /api/tags/:id
+func UpdateTag(c *gin.Context) {
+	user := currentUser(c)
+
+	var tag model.Tag
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	var input struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if name := strings.TrimSpace(input.Name); name != "" {
+		var count int64
+		database.DB.Model(&model.Tag{}).
+			Where("user_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", user.ID, name, tag.ID).
+			Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Tag already exists"})
+			return
+		}
+		tag.Name = name
+	}
+	if input.Color != "" {
+		tag.Color = input.Color
+	}
+
+	database.DB.Save(&tag)
+
+	c.JSON(http.StatusOK, gin.H{"data": tag})
+}
+
+// DELETE /api/tags/:id
+func DeleteTag(c *gin.Context) {
+	user := currentUser(c)
+
+	var tag model.Tag
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	database.DB.Model(&tag).Association("Jobs").Clear()
+	database.DB.Delete(&tag)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted"})
+}
+
+// PUT /api/jobs/:id/tags
+// Body: { "tags": ["remote", "dream job"] } — ganti semua tag job, tag baru otomatis dibuat
+func SetJobTags(c *gin.Context) {
+	user := currentUser(c)
+
+	var job model.Job
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), user.ID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	var input struct {
+		Tags []string `json:"tags"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tags, err := findOrCreateTags(user.ID, input.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tags"})
+		return
+	}
+
+	if err := database.DB.Model(&job).Association("Tags").Replace(tags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
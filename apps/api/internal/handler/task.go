@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/myfarism/lamarr-api/internal/task"
+	"github.com/myfarism/lamarr-api/pkg/queue"
+)
+
+// wantsAsync — endpoint AI jalan di worker kalau dipanggil dengan ?async=true
+func wantsAsync(c *gin.Context) bool {
+	v := c.Query("async")
+	return v == "true" || v == "1"
+}
+
+// enqueueTask balikin 202 + task ID, client poll ke GET /api/tasks/:id
+func enqueueTask(c *gin.Context, taskType string, payload any) {
+	if !queue.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Background queue is not configured"})
+		return
+	}
+
+	info, err := task.Enqueue(c.Request.Context(), taskType, payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue task"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"data": gin.H{
+			"task_id":    info.ID,
+			"type":       info.Type,
+			"state":      info.State.String(),
+			"status_url": "/api/tasks/" + info.ID,
+		},
+	})
+}
+
+// GET /api/tasks/:id
+func GetTask(c *gin.Context) {
+	user := currentUser(c)
+
+	if !queue.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Background queue is not configured"})
+		return
+	}
+
+	info, err := queue.Inspector.GetTaskInfo(queue.QueueAI, c.Param("id"))
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get task"})
+		return
+	}
+
+	// Task milik user lain dianggap tidak ada
+	var owner task.Owner
+	if err := json.Unmarshal(info.Payload, &owner); err != nil || owner.UserID != user.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	data := gin.H{
+		"task_id": info.ID,
+		"type":    info.Type,
+		"state":   info.State.String(),
+		"retried": info.Retried,
+	}
+	if info.LastErr != "" {
+		data["error"] = info.LastErr
+	}
+	if len(info.Result) > 0 {
+		data["result"] = json.RawMessage(info.Result)
+	}
+	if !info.CompletedAt.IsZero() {
+		data["completed_at"] = info.CompletedAt
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	// "github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
 )
 
 func GetMe(c *gin.Context) {
@@ -13,3 +15,44 @@ func GetMe(c *gin.Context) {
 		"data": user,
 	})
 }
+
+// PATCH /api/me/settings
+// Body: { "auto_ghost_enabled": false, "ghost_after_days": 21, "preferred_language": "id" }
+func UpdateSettings(c *gin.Context) {
+	user := currentUser(c)
+
+	var input struct {
+		AutoGhostEnabled *bool `json:"auto_ghost_enabled"`
+		GhostAfterDays   *int  `json:"ghost_after_days" binding:"omitempty,min=3,max=180"`
+
+		PreferredLanguage *string `json:"preferred_language"` // en | id
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.AutoGhostEnabled != nil {
+		updates["auto_ghost_enabled"] = *input.AutoGhostEnabled
+	}
+	if input.GhostAfterDays != nil {
+		updates["ghost_after_days"] = *input.GhostAfterDays
+	}
+	if input.PreferredLanguage != nil {
+		language, err := ai.NormalizeLanguage(*input.PreferredLanguage)
+		if err != nil || language == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "preferred_language must be en or id"})
+			return
+		}
+		updates["preferred_language"] = language
+	}
+
+	if len(updates) > 0 {
+		database.DB.Model(&model.User{}).Where("id = ?", user.ID).Updates(updates)
+	}
+
+	database.DB.First(&user, user.ID)
+	c.JSON(http.StatusOK, gin.H{"data": user})
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// AICacheEntry — fallback cache LLM/embedding di Postgres kalau Redis tidak tersedia
+type AICacheEntry struct {
+	Key       string    `json:"key" gorm:"primaryKey"`
+	Kind      string    `json:"kind" gorm:"index"`
+	Value     string    `json:"-" gorm:"type:text"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
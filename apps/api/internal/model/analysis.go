@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// JobAnalysis — hasil gap analysis yang pernah dijalankan untuk job, beserta versi prompt-nya
+type JobAnalysis struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	JobID           uint           `json:"job_id" gorm:"index;not null"`
+	UserID          uint           `json:"user_id" gorm:"index;not null"`
+	CvVersionID     *uint          `json:"cv_version_id"`
+	MatchPercentage int            `json:"match_percentage"`
+	Strengths       pq.StringArray `json:"strengths" gorm:"type:text[]"`
+	Gaps            pq.StringArray `json:"gaps" gorm:"type:text[]"`
+	Suggestion      string         `json:"suggestion" gorm:"type:text"`
+	Verdict         string         `json:"verdict" gorm:"type:text"`
+	Language        string         `json:"language"`
+	PromptVersion   string         `json:"prompt_version"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+// ParseResult — hasil parse/scrape yang dikirim ke client. Job yang dibuat dari hasil ini
+// merujuk lewat parse_id, jadi versi prompt-nya dicatat server, bukan dari client.
+type ParseResult struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"index;not null"`
+	PromptVersion string    `json:"prompt_version"`
+	Result        string    `json:"result" gorm:"type:jsonb"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Company — perusahaan yang sudah dinormalisasi, supaya "PT Tokopedia" dan "tokopedia" dihitung satu.
+// Job.Company tetap menyimpan nama apa adanya, Job.CompanyID yang dipakai untuk agregasi.
+type Company struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	UserID         uint           `json:"user_id" gorm:"uniqueIndex:idx_company_user_name;not null"`
+	Name           string         `json:"name" gorm:"not null"`
+	NormalizedName string         `json:"normalized_name" gorm:"uniqueIndex:idx_company_user_name;not null"`
+	Aliases        pq.StringArray `json:"aliases" gorm:"type:text[]"`
+	Jobs           []Job          `json:"jobs,omitempty" gorm:"foreignKey:CompanyID"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
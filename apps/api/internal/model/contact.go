@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Contact — recruiter, hiring manager, referrer, dll. Bisa terhubung ke banyak job.
+type Contact struct {
+	ID           uint                 `json:"id" gorm:"primaryKey"`
+	UserID       uint                 `json:"user_id" gorm:"index;not null"`
+	Name         string               `json:"name" gorm:"not null"`
+	Role         string               `json:"role"` // recruiter, hiring_manager, referrer, ...
+	Email        string               `json:"email"`
+	Phone        string               `json:"phone"`
+	LinkedInURL  string               `json:"linkedin_url"`
+	Company      string               `json:"company"`
+	Notes        string               `json:"notes" gorm:"type:text"`
+	Jobs         []Job                `json:"jobs,omitempty" gorm:"many2many:job_contacts;"`
+	Interactions []ContactInteraction `json:"interactions,omitempty" gorm:"foreignKey:ContactID"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// ContactInteraction — log komunikasi dengan contact (email, call, chat LinkedIn, ...)
+type ContactInteraction struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ContactID  uint      `json:"contact_id" gorm:"index;not null"`
+	UserID     uint      `json:"user_id" gorm:"index;not null"`
+	JobID      *uint     `json:"job_id" gorm:"index"`
+	Channel    string    `json:"channel"`
+	Summary    string    `json:"summary" gorm:"type:text"`
+	HappenedAt time.Time `json:"happened_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
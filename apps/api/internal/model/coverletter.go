@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// CoverLetter — satu versi cover letter untuk job. Generate ulang atau edit manual
+// selalu membuat versi baru, jadi histori tetap ada.
+type CoverLetter struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	JobID       uint      `json:"job_id" gorm:"uniqueIndex:idx_cover_letter_version;not null"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	Version     int       `json:"version" gorm:"uniqueIndex:idx_cover_letter_version;not null"`
+	Content     string    `json:"content" gorm:"type:text"`
+	Tone        string    `json:"tone"`
+	Language    string    `json:"language"`
+	Length      string    `json:"length"`
+	Source      string    `json:"source"` // ai | edited
+	CvVersionID *uint     `json:"cv_version_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	PromptVersion string `json:"prompt_version"` // versi template prompt yang menghasilkan letter ini
+}
+
+const (
+	CoverLetterSourceAI     = "ai"
+	CoverLetterSourceEdited = "edited"
+)
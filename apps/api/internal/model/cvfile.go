@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// CvFile — file CV asli (PDF/DOCX) yang di-upload user, disimpan apa adanya
+type CvFile struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Data        []byte    `json:"-" gorm:"type:bytea"`
+	CreatedAt   time.Time `json:"created_at"`
+}
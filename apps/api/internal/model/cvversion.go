@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// CvVersion — satu varian CV (misal "Backend", "Data Engineer"). Tepat satu versi
+// per user ditandai default, dan teksnya disalin ke User.CvText.
+type CvVersion struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"index;not null"`
+	Label     string    `json:"label" gorm:"not null"`
+	Text      string    `json:"text" gorm:"type:text"`
+	CvFileID  *uint     `json:"cv_file_id"`
+	CvFile    *CvFile   `json:"cv_file,omitempty" gorm:"foreignKey:CvFileID"`
+	IsDefault bool      `json:"is_default" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Vector disimpan di kolom pgvector. Dimensi sengaja tidak dikunci di kolom,
+// jadi ganti model embedding tidak perlu migrasi — cukup bandingkan per Model.
+type Vector []float64
+
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	parts := make([]string, len(v))
+	for i, f := range v {
+		parts[i] = strconv.FormatFloat(f, 'f', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]", nil
+}
+
+func (v *Vector) Scan(src any) error {
+	var s string
+	switch val := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return fmt.Errorf("cannot scan %T into Vector", src)
+	}
+
+	s = strings.Trim(strings.TrimSpace(s), "[]")
+	if s == "" {
+		*v = Vector{}
+		return nil
+	}
+
+	parts := strings.Split(s, ",")
+	out := make(Vector, len(parts))
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return fmt.Errorf("invalid vector element %q: %w", p, err)
+		}
+		out[i] = f
+	}
+	*v = out
+	return nil
+}
+
+// CvEmbedding — embedding User.CvText, satu baris per model
+type CvEmbedding struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"uniqueIndex:idx_cv_embedding;not null"`
+	Model       string    `json:"model" gorm:"uniqueIndex:idx_cv_embedding;not null"`
+	Dimension   int       `json:"dimension"`
+	ContentHash string    `json:"content_hash"`
+	Vector      Vector    `json:"-" gorm:"type:vector"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CvVersionEmbedding — embedding teks CvVersion, satu baris per model
+type CvVersionEmbedding struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	CvVersionID uint      `json:"cv_version_id" gorm:"uniqueIndex:idx_cv_version_embedding;not null"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	Model       string    `json:"model" gorm:"uniqueIndex:idx_cv_version_embedding;not null"`
+	Dimension   int       `json:"dimension"`
+	ContentHash string    `json:"content_hash"`
+	Vector      Vector    `json:"-" gorm:"type:vector"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// JobEmbedding — embedding per field job (requirements / description), satu baris per model
+type JobEmbedding struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	JobID       uint      `json:"job_id" gorm:"uniqueIndex:idx_job_embedding;not null"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	Field       string    `json:"field" gorm:"uniqueIndex:idx_job_embedding;not null"`
+	Model       string    `json:"model" gorm:"uniqueIndex:idx_job_embedding;not null"`
+	Dimension   int       `json:"dimension"`
+	ContentHash string    `json:"content_hash"`
+	Vector      Vector    `json:"-" gorm:"type:vector"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+const (
+	EmbeddingFieldRequirements = "requirements"
+	EmbeddingFieldDescription  = "description"
+)
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DefaultTimezone dipakai kalau interview/user belum punya timezone
+const DefaultTimezone = "Asia/Jakarta"
+
+type Interview struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	JobID        uint           `json:"job_id" gorm:"index;not null"`
+	UserID       uint           `json:"user_id" gorm:"index;not null"`
+	Job          *Job           `json:"job,omitempty" gorm:"foreignKey:JobID"`
+	RoundName    string         `json:"round_name" gorm:"not null"`
+	StartsAt     time.Time      `json:"starts_at" gorm:"index;not null"`
+	EndsAt       time.Time      `json:"ends_at" gorm:"not null"`
+	Timezone     string         `json:"timezone"` // IANA, misal Asia/Jakarta
+	Location     string         `json:"location"`
+	MeetingLink  string         `json:"meeting_link"`
+	Interviewers pq.StringArray `json:"interviewers" gorm:"type:text[]"`
+	Notes        string         `json:"notes" gorm:"type:text"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
@@ -19,8 +19,12 @@ type Job struct {
 	UserID       uint           `json:"user_id" gorm:"index;not null"`
 	User         User           `json:"-" gorm:"foreignKey:UserID"`
 	Timelines    []JobTimeline  `json:"timelines,omitempty" gorm:"foreignKey:JobID"` 
+	Interviews   []Interview    `json:"interviews,omitempty" gorm:"foreignKey:JobID"`
+	Contacts     []Contact      `json:"contacts,omitempty" gorm:"many2many:job_contacts;"`
+	Tags         []Tag          `json:"tags,omitempty" gorm:"many2many:job_tags;"`
 	Title        string         `json:"title" gorm:"not null"`
 	Company      string         `json:"company" gorm:"not null"`
+	CompanyID    *uint          `json:"company_id" gorm:"index"`
 	URL          string         `json:"url"`
 	Platform     string         `json:"platform"`
 	Status       JobStatus      `json:"status" gorm:"default:applied"`
@@ -28,7 +32,21 @@ type Job struct {
 	Requirements string         `json:"requirements" gorm:"type:text"`
 	SalaryMin    *int           `json:"salary_min"`
 	SalaryMax    *int           `json:"salary_max"`
+
+	// Gaji dalam mata uang & periode aslinya. *_normalized = per bulan dalam base currency
+	// (SALARY_BASE_CURRENCY), dihitung dari tabel exchange_rates; nil kalau kurs belum ada.
+	SalaryCurrency      string `json:"salary_currency" gorm:"size:3;default:IDR"` // ISO 4217
+	SalaryPeriod        string `json:"salary_period" gorm:"default:month"`        // hour | day | week | month | year
+	SalaryType          string `json:"salary_type"`                               // gross | net, kosong = tidak disebut
+	SalaryMinNormalized *int   `json:"salary_min_normalized"`
+	SalaryMaxNormalized *int   `json:"salary_max_normalized"`
+
 	MatchScore   *float64       `json:"match_score"`
+	CvVersionID  *uint          `json:"cv_version_id" gorm:"index"` // versi CV yang dikirim ke lowongan ini
+
+	ParsePromptVersion string `json:"parse_prompt_version"` // versi prompt parse kalau job dibuat dari hasil AI
+	Language           string `json:"language"`             // bahasa lowongan (ISO 639-1), dari parser atau input user
+
 	Notes        string         `json:"notes" gorm:"type:text"`
 	AppliedAt    time.Time      `json:"applied_at"`
 	Deadline     *time.Time     `json:"deadline"`
@@ -5,11 +5,15 @@ import (
 )
 
 type User struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	FirebaseUID string    `json:"firebase_uid" gorm:"uniqueIndex;not null"`
-	Email       string    `json:"email" gorm:"uniqueIndex;not null"`
-	Name        string    `json:"name"`
-	CvText      string    `json:"cv_text" gorm:"type:text"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	FirebaseUID       string    `json:"firebase_uid" gorm:"uniqueIndex;not null"`
+	Email             string    `json:"email" gorm:"uniqueIndex;not null"`
+	Name              string    `json:"name"`
+	CvText            string    `json:"cv_text" gorm:"type:text"`
+	AutoGhostEnabled  bool      `json:"auto_ghost_enabled" gorm:"not null;default:true"` // job tanpa kabar otomatis dipindah ke ghosted
+	GhostAfterDays    int       `json:"ghost_after_days" gorm:"not null;default:14"`
+	CalendarToken     *string   `json:"-" gorm:"uniqueIndex"`                          // secret buat URL feed ICS
+	PreferredLanguage string    `json:"preferred_language" gorm:"not null;default:en"` // bahasa output AI: en | id
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Periode gaji di Job. Perbandingan & analytics dinormalisasi ke per bulan.
+const (
+	SalaryPeriodHour  = "hour"
+	SalaryPeriodDay   = "day"
+	SalaryPeriodWeek  = "week"
+	SalaryPeriodMonth = "month"
+	SalaryPeriodYear  = "year"
+)
+
+// Gross = sebelum pajak, net = take home pay. Kosong = tidak disebut di lowongan.
+const (
+	SalaryTypeGross = "gross"
+	SalaryTypeNet   = "net"
+)
+
+var SalaryPeriods = map[string]bool{
+	SalaryPeriodHour: true, SalaryPeriodDay: true, SalaryPeriodWeek: true,
+	SalaryPeriodMonth: true, SalaryPeriodYear: true,
+}
+
+var SalaryTypes = map[string]bool{SalaryTypeGross: true, SalaryTypeNet: true}
+
+// ExchangeRate — kurs yang di-maintain admin: 1 unit Currency = Rate unit base currency (SALARY_BASE_CURRENCY)
+type ExchangeRate struct {
+	Currency  string    `json:"currency" gorm:"primaryKey;size:3"`
+	Rate      float64   `json:"rate" gorm:"not null"`
+	UpdatedBy string    `json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,65 @@
+package model
+
+var AllStatuses = []JobStatus{
+	StatusApplied,
+	StatusScreening,
+	StatusInterview,
+	StatusOffer,
+	StatusRejected,
+	StatusGhosted,
+}
+
+// statusTransitions = perpindahan "maju" yang boleh tanpa override.
+// Perpindahan lain (misal offer → applied) dianggap mundur dan harus pakai override.
+var statusTransitions = map[JobStatus][]JobStatus{
+	StatusApplied:   {StatusScreening, StatusInterview, StatusOffer, StatusRejected, StatusGhosted},
+	StatusScreening: {StatusInterview, StatusOffer, StatusRejected, StatusGhosted},
+	StatusInterview: {StatusOffer, StatusRejected, StatusGhosted},
+	StatusOffer:     {StatusRejected},
+	StatusRejected:  {},
+	// Perusahaan yang nge-ghost kadang balik lagi
+	StatusGhosted: {StatusScreening, StatusInterview, StatusOffer, StatusRejected},
+}
+
+// statusOrder urutan tahap pipeline. Rejected/ghosted adalah hasil akhir, bukan tahap.
+var statusOrder = map[JobStatus]int{
+	StatusApplied:   0,
+	StatusScreening: 1,
+	StatusInterview: 2,
+	StatusOffer:     3,
+}
+
+func (s JobStatus) IsValid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// NextStatuses daftar status yang boleh dituju dari s tanpa override
+func (s JobStatus) NextStatuses() []JobStatus {
+	return statusTransitions[s]
+}
+
+func (s JobStatus) CanTransitionTo(to JobStatus) bool {
+	for _, next := range statusTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OverrideLabel arah perpindahan s → to untuk catatan timeline override:
+// "moved back", "moved forward", "reopened" (dari rejected/ghosted) atau "changed"
+func (s JobStatus) OverrideLabel(to JobStatus) string {
+	from, fromStage := statusOrder[s]
+	dest, toStage := statusOrder[to]
+	switch {
+	case fromStage && toStage && dest < from:
+		return "moved back"
+	case fromStage && toStage:
+		return "moved forward"
+	case toStage:
+		return "reopened"
+	}
+	return "changed"
+}
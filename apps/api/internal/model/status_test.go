@@ -0,0 +1,23 @@
+package model
+
+import "testing"
+
+func TestOverrideLabel(t *testing.T) {
+	tests := []struct {
+		from, to JobStatus
+		want     string
+	}{
+		{StatusOffer, StatusApplied, "moved back"},
+		{StatusInterview, StatusScreening, "moved back"},
+		{StatusScreening, StatusOffer, "moved forward"},
+		{StatusRejected, StatusInterview, "reopened"},
+		{StatusGhosted, StatusApplied, "reopened"},
+		{StatusOffer, StatusGhosted, "changed"},
+		{StatusRejected, StatusGhosted, "changed"},
+	}
+	for _, tt := range tests {
+		if got := tt.from.OverrideLabel(tt.to); got != tt.want {
+			t.Errorf("%s → %s = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
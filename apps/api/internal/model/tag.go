@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Tag — label bebas milik user ("remote", "dream job", "via bootcamp"), many-to-many ke Job
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_tag_user_name;not null"`
+	Name      string    `json:"name" gorm:"uniqueIndex:idx_tag_user_name;not null"`
+	Color     string    `json:"color"`
+	Jobs      []Job     `json:"jobs,omitempty" gorm:"many2many:job_tags;"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
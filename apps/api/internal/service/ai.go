@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// Dipakai bareng oleh handler HTTP dan worker Asynq
+
+var (
+	ErrNoCV           = errors.New("user has no CV text")
+	ErrNoRequirements = errors.New("job has no requirements")
+	ErrNoContent      = errors.New("no content scraped from URL")
+)
+
+// ScrapeAndParse scrape URL lowongan lalu parse pakai LLM
+func ScrapeAndParse(ctx context.Context, url string) (*ai.ParsedJob, error) {
+	scraped, err := ScrapeJob(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kirim RawText (bukan Description) supaya AI dapat data penuh
+	sourceText := scraped.RawText
+	if sourceText == "" {
+		return nil, ErrNoContent
+	}
+
+	parsed, err := ai.ParseJobDescription(ctx, sourceText)
+	if err != nil {
+		return nil, fmt.Errorf("AI parsing failed: %w", err)
+	}
+
+	// Override dengan data scraper jika lebih reliable
+	if scraped.Platform != "" {
+		parsed.Platform = scraped.Platform
+	}
+	if scraped.Title != "" {
+		parsed.Title = scraped.Title
+	}
+
+	return parsed, nil
+}
+
+// OutputLanguage bahasa output AI: override per request, lalu preferensi user, lalu default
+func OutputLanguage(user model.User, override string) string {
+	if override != "" {
+		return override
+	}
+	if user.PreferredLanguage != "" {
+		return user.PreferredLanguage
+	}
+	return ai.DefaultLanguage
+}
+
+// AnalyzeJob jalankan gap analysis lalu update match_score dari embedding tersimpan
+// CV yang dipakai = versi yang dikirim ke job ini, atau CV default.
+// language kosong = bahasa pilihan user.
+func AnalyzeJob(ctx context.Context, user model.User, job *model.Job, language string) (*ai.GapAnalysis, error) {
+	return AnalyzeJobStream(ctx, user, job, language, nil)
+}
+
+// AnalyzeJobStream sama seperti AnalyzeJob, output LLM dikirim ke onToken sambil jalan
+func AnalyzeJobStream(ctx context.Context, user model.User, job *model.Job, language string, onToken ai.StreamFunc) (*ai.GapAnalysis, error) {
+	cvText, version := CVForJob(user, job)
+	if cvText == "" {
+		return nil, ErrNoCV
+	}
+	if job.Requirements == "" {
+		return nil, ErrNoRequirements
+	}
+
+	// Gap analysis pakai LLM
+	analysis, err := ai.AnalyzeGapStream(ctx, cvText, job.Requirements, OutputLanguage(user, language), onToken)
+	if err != nil {
+		return nil, err
+	}
+	saveJobAnalysis(job, version, analysis)
+
+	// Hitung embedding similarity juga — vector diambil dari pgvector kalau teks belum berubah
+	var cvEmbedding *ai.Embedding
+	if version != nil {
+		cvEmbedding, err = EmbedCVVersion(ctx, version)
+	} else {
+		cvEmbedding, err = EmbedCV(ctx, user.ID, cvText)
+	}
+	if err == nil {
+		jdEmbedding, err := EmbedJobField(ctx, job, model.EmbeddingFieldRequirements)
+		if err == nil {
+			if score, err := ai.Similarity(cvEmbedding, jdEmbedding); err == nil {
+				// Update match score di database
+				database.DB.Model(job).Update("match_score", score)
+			}
+		}
+	}
+
+	return analysis, nil
+}
+
+// SaveParseResult simpan hasil parse/scrape dan isi parsed.ParseID. Gagal simpan tidak
+// menggagalkan parse, job yang dibuat dari hasil itu cuma tidak punya versi prompt.
+func SaveParseResult(userID uint, parsed *ai.ParsedJob) {
+	parsed.ParseID = 0
+	body, err := json.Marshal(parsed)
+	if err != nil {
+		log.Printf("encode parse result failed: %v", err)
+		return
+	}
+
+	record := model.ParseResult{
+		UserID:        userID,
+		PromptVersion: parsed.PromptVersion,
+		Result:        string(body),
+	}
+	if err := database.DB.Create(&record).Error; err != nil {
+		log.Printf("save parse result failed: %v", err)
+		return
+	}
+	parsed.ParseID = record.ID
+}
+
+// ParsePromptVersion versi prompt dari hasil parse milik user
+func ParsePromptVersion(userID, parseID uint) (string, error) {
+	var record model.ParseResult
+	err := database.DB.Select("prompt_version").
+		Where("id = ? AND user_id = ?", parseID, userID).
+		First(&record).Error
+	return record.PromptVersion, err
+}
+
+// saveJobAnalysis simpan hasil gap analysis beserta versi prompt-nya. Gagal simpan tidak menggagalkan analisis.
+func saveJobAnalysis(job *model.Job, version *model.CvVersion, analysis *ai.GapAnalysis) {
+	record := model.JobAnalysis{
+		JobID:           job.ID,
+		UserID:          job.UserID,
+		MatchPercentage: analysis.MatchPercentage,
+		Strengths:       analysis.Strengths,
+		Gaps:            analysis.Gaps,
+		Suggestion:      analysis.Suggestion,
+		Verdict:         analysis.Verdict,
+		Language:        analysis.Language,
+		PromptVersion:   analysis.PromptVersion,
+	}
+	if version != nil {
+		record.CvVersionID = &version.ID
+	}
+	if err := database.DB.Create(&record).Error; err != nil {
+		log.Printf("save gap analysis for job %d failed: %v", job.ID, err)
+	}
+}
+
+// RescoreUser embed ulang CV + semua job yang belum punya embedding, lalu hitung ulang match_score
+func RescoreUser(ctx context.Context, userID uint) error {
+	var user model.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	if err := RefreshCV(ctx, user.ID, user.CvText); err != nil {
+		return err
+	}
+	if err := RefreshCvVersions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	missing, err := MissingJobEmbeddings(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := BackfillJobEmbeddings(ctx, user.ID, missing); err != nil {
+		return err
+	}
+
+	return RescoreJobs(user.ID)
+}
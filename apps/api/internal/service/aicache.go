@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// PurgeAICache hapus entry cache AI di Postgres yang sudah kedaluwarsa
+func PurgeAICache(ctx context.Context) (int64, error) {
+	return ai.NewPostgresCache(database.DB).PurgeExpired(ctx)
+}
+
+// StartCachePurger fallback kalau worker/Redis tidak jalan: purge pakai ticker di proses server
+func StartCachePurger(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := PurgeAICache(context.Background())
+			if err != nil {
+				log.Printf("ai cache purge failed: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("🧹 ai cache purge: %d expired entries deleted", n)
+			}
+		}
+	}()
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// Urutan tahap funnel. Rejected/ghosted bukan tahap, tapi outcome.
+var FunnelStages = []model.JobStatus{
+	model.StatusApplied,
+	model.StatusScreening,
+	model.StatusInterview,
+	model.StatusOffer,
+}
+
+type FunnelStage struct {
+	Stage                 model.JobStatus `json:"stage"`
+	Reached               int             `json:"reached"`
+	ConversionFromPrev    *float64        `json:"conversion_from_previous"`
+	ConversionFromApplied float64         `json:"conversion_from_applied"`
+}
+
+type Funnel struct {
+	Total    int           `json:"total"`
+	Stages   []FunnelStage `json:"stages"`
+	Rejected int           `json:"rejected"`
+	Ghosted  int           `json:"ghosted"`
+}
+
+type PlatformFunnel struct {
+	Platform string `json:"platform"`
+	Funnel
+}
+
+type MonthFunnel struct {
+	Month string `json:"month"`
+	Funnel
+}
+
+type FunnelReport struct {
+	Funnel
+	ByPlatform []PlatformFunnel `json:"by_platform"`
+	ByMonth    []MonthFunnel    `json:"by_month"`
+}
+
+// jobHistory = satu job + semua stage yang pernah dia lewati (dari JobTimeline)
+type jobHistory struct {
+	job    model.Job
+	stages map[model.JobStatus]bool
+}
+
+// furthestStage index tahap funnel terjauh. Job yang lompat applied → interview
+// dihitung juga sudah lewat screening.
+func (h jobHistory) furthestStage() int {
+	furthest := 0
+	for i, stage := range FunnelStages {
+		if h.stages[stage] {
+			furthest = i
+		}
+	}
+	return furthest
+}
+
+func loadJobHistories(jobs []model.Job) ([]jobHistory, error) {
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+
+	var timelines []model.JobTimeline
+	if err := database.DB.Where("job_id IN ?", ids).Find(&timelines).Error; err != nil {
+		return nil, err
+	}
+
+	histories := make(map[uint]*jobHistory, len(jobs))
+	result := make([]jobHistory, len(jobs))
+	for i, job := range jobs {
+		result[i] = jobHistory{job: job, stages: map[model.JobStatus]bool{
+			model.StatusApplied: true,
+			job.Status:          true,
+		}}
+		histories[job.ID] = &result[i]
+	}
+	for _, t := range timelines {
+		if h, ok := histories[t.JobID]; ok {
+			h.stages[model.JobStatus(t.Stage)] = true
+		}
+	}
+
+	return result, nil
+}
+
+func buildFunnel(histories []jobHistory) Funnel {
+	reached := make([]int, len(FunnelStages))
+	funnel := Funnel{Total: len(histories)}
+
+	for _, h := range histories {
+		for i := 0; i <= h.furthestStage(); i++ {
+			reached[i]++
+		}
+		if h.stages[model.StatusRejected] {
+			funnel.Rejected++
+		}
+		if h.stages[model.StatusGhosted] {
+			funnel.Ghosted++
+		}
+	}
+
+	for i, stage := range FunnelStages {
+		s := FunnelStage{Stage: stage, Reached: reached[i]}
+		if i > 0 {
+			rate := ratio(reached[i], reached[i-1])
+			s.ConversionFromPrev = &rate
+		}
+		s.ConversionFromApplied = ratio(reached[i], reached[0])
+		funnel.Stages = append(funnel.Stages, s)
+	}
+
+	return funnel
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// BuildFunnelReport hitung funnel dari histori JobTimeline, bukan dari status sekarang,
+// jadi job yang sudah rejected setelah interview tetap terhitung sampai interview.
+func BuildFunnelReport(userID uint) (*FunnelReport, error) {
+	var jobs []model.Job
+	if err := database.DB.Where("user_id = ?", userID).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	histories, err := loadJobHistories(jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FunnelReport{
+		Funnel:     buildFunnel(histories),
+		ByPlatform: []PlatformFunnel{},
+		ByMonth:    []MonthFunnel{},
+	}
+
+	byPlatform := map[string][]jobHistory{}
+	byMonth := map[string][]jobHistory{}
+	for _, h := range histories {
+		platform := h.job.Platform
+		if platform == "" {
+			platform = "unknown"
+		}
+		byPlatform[platform] = append(byPlatform[platform], h)
+		month := h.job.AppliedAt.Format("2006-01")
+		byMonth[month] = append(byMonth[month], h)
+	}
+
+	for platform, hs := range byPlatform {
+		report.ByPlatform = append(report.ByPlatform, PlatformFunnel{Platform: platform, Funnel: buildFunnel(hs)})
+	}
+	sort.Slice(report.ByPlatform, func(i, j int) bool {
+		return report.ByPlatform[i].Total > report.ByPlatform[j].Total
+	})
+
+	for month, hs := range byMonth {
+		report.ByMonth = append(report.ByMonth, MonthFunnel{Month: month, Funnel: buildFunnel(hs)})
+	}
+	sort.Slice(report.ByMonth, func(i, j int) bool {
+		return report.ByMonth[i].Month < report.ByMonth[j].Month
+	})
+
+	return report, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// NewCalendarToken bikin secret baru untuk URL feed ICS
+func NewCalendarToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// BuildCalendar render semua interview + deadline job user dalam format iCalendar (RFC 5545)
+func BuildCalendar(userID uint) (string, error) {
+	var interviews []model.Interview
+	err := database.DB.Preload("Job").
+		Joins("JOIN jobs ON jobs.id = interviews.job_id AND jobs.deleted_at IS NULL").
+		Where("interviews.user_id = ?", userID).
+		Order("starts_at asc").
+		Find(&interviews).Error
+	if err != nil {
+		return "", err
+	}
+
+	var jobs []model.Job
+	err = database.DB.
+		Where("user_id = ? AND deadline IS NOT NULL", userID).
+		Where("status NOT IN ?", []model.JobStatus{model.StatusRejected, model.StatusGhosted}).
+		Find(&jobs).Error
+	if err != nil {
+		return "", err
+	}
+
+	return writeCalendar(interviews, jobs, calendarLocation(), time.Now()), nil
+}
+
+// calendarLocation timezone untuk tanggal event seharian (deadline). User belum punya setting timezone,
+// jadi pakai DefaultTimezone — bukan timezone interview yang bisa saja di zona perusahaan lain.
+func calendarLocation() *time.Location {
+	if loc, err := time.LoadLocation(model.DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+func writeCalendar(interviews []model.Interview, jobs []model.Job, loc *time.Location, now time.Time) string {
+	cal := &icalWriter{}
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//Lamarr//Job Tracker//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.line("X-WR-CALNAME:Lamarr")
+
+	// Interview ditulis dengan TZID supaya jam lokalnya tetap benar walau ada DST
+	zones := &icalZones{}
+	for _, iv := range interviews {
+		zones.add(iv.Timezone, iv.StartsAt, iv.EndsAt)
+	}
+	zones.write(cal)
+
+	for _, iv := range interviews {
+		summary := "Interview: " + iv.RoundName
+		if iv.Job != nil {
+			summary += " — " + iv.Job.Title + " @ " + iv.Job.Company
+		}
+
+		var desc []string
+		if len(iv.Interviewers) > 0 {
+			desc = append(desc, "Interviewers: "+strings.Join(iv.Interviewers, ", "))
+		}
+		if iv.MeetingLink != "" {
+			desc = append(desc, "Link: "+iv.MeetingLink)
+		}
+		if iv.Notes != "" {
+			desc = append(desc, iv.Notes)
+		}
+
+		location := iv.Location
+		if location == "" {
+			location = iv.MeetingLink
+		}
+
+		cal.line("BEGIN:VEVENT")
+		cal.line(fmt.Sprintf("UID:interview-%d@lamarr", iv.ID))
+		cal.line("DTSTAMP:" + icalTime(now))
+		cal.line("LAST-MODIFIED:" + icalTime(iv.UpdatedAt))
+		cal.line("DTSTART" + zones.time(iv.Timezone, iv.StartsAt))
+		cal.line("DTEND" + zones.time(iv.Timezone, iv.EndsAt))
+		cal.line("SUMMARY:" + icalEscape(summary))
+		if location != "" {
+			cal.line("LOCATION:" + icalEscape(location))
+		}
+		if link, ok := icalURL(iv.MeetingLink); ok {
+			cal.line("URL:" + link)
+		}
+		if len(desc) > 0 {
+			cal.line("DESCRIPTION:" + icalEscape(strings.Join(desc, "\n")))
+		}
+		cal.line("END:VEVENT")
+	}
+
+	// Deadline = event seharian, tanggalnya dihitung di timezone user
+	for _, job := range jobs {
+		deadline := job.Deadline.In(loc)
+		day := deadline.Format("20060102")
+		next := deadline.AddDate(0, 0, 1).Format("20060102")
+
+		cal.line("BEGIN:VEVENT")
+		cal.line(fmt.Sprintf("UID:deadline-%d@lamarr", job.ID))
+		cal.line("DTSTAMP:" + icalTime(now))
+		cal.line("DTSTART;VALUE=DATE:" + day)
+		cal.line("DTEND;VALUE=DATE:" + next)
+		cal.line("SUMMARY:" + icalEscape("Deadline: "+job.Title+" @ "+job.Company))
+		if link, ok := icalURL(job.URL); ok {
+			cal.line("URL:" + link)
+		}
+		cal.line("TRANSP:TRANSPARENT")
+		cal.line("END:VEVENT")
+	}
+
+	cal.line("END:VCALENDAR")
+	return cal.String()
+}
+
+type icalWriter struct {
+	strings.Builder
+}
+
+// line tulis satu content line dengan CRLF, dilipat tiap 75 byte sesuai RFC 5545
+func (w *icalWriter) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		// jangan potong di tengah karakter UTF-8
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // baris lanjutan diawali spasi
+	}
+	w.WriteString(s + "\r\n")
+}
+
+// icalURL cuma terima URL http(s) absolut; URI tidak di-escape seperti TEXT jadi nilai lain dibuang
+func icalURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+	return u.String(), true
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func icalEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\r", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// icalZones kumpulkan VTIMEZONE untuk timezone yang dipakai interview.
+// Observance diambil dari periode zona (ZoneBounds) tiap waktu event, jadi cukup untuk event yang ada.
+type icalZones struct {
+	zones map[string]*icalZone
+	names []string
+}
+
+type icalZone struct {
+	loc         *time.Location
+	observances map[int64]icalObservance // key: awal periode zona (unix)
+}
+
+type icalObservance struct {
+	start      time.Time
+	dst        bool
+	name       string
+	offsetFrom int
+	offsetTo   int
+}
+
+func (z *icalZones) location(name string) (*time.Location, bool) {
+	if zone, ok := z.zones[name]; ok {
+		return zone.loc, true
+	}
+	return nil, false
+}
+
+func (z *icalZones) add(name string, times ...time.Time) {
+	if name == "" || name == "UTC" {
+		return
+	}
+	zone, ok := z.zones[name]
+	if !ok {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return
+		}
+		if z.zones == nil {
+			z.zones = map[string]*icalZone{}
+		}
+		zone = &icalZone{loc: loc, observances: map[int64]icalObservance{}}
+		z.zones[name] = zone
+		z.names = append(z.names, name)
+	}
+
+	for _, t := range times {
+		local := t.In(zone.loc)
+		abbr, offset := local.Zone()
+		start, _ := local.ZoneBounds()
+		obs := icalObservance{dst: local.IsDST(), name: abbr, offsetFrom: offset, offsetTo: offset}
+		if start.IsZero() {
+			obs.start = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+		} else {
+			_, obs.offsetFrom = start.Add(-time.Second).Zone()
+			// DTSTART observance = jam lokal sebelum transisi
+			obs.start = start.In(time.FixedZone("", obs.offsetFrom))
+		}
+		zone.observances[start.Unix()] = obs
+	}
+}
+
+func (z *icalZones) write(cal *icalWriter) {
+	sort.Strings(z.names)
+	for _, name := range z.names {
+		zone := z.zones[name]
+		keys := make([]int64, 0, len(zone.observances))
+		for k := range zone.observances {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+		cal.line("BEGIN:VTIMEZONE")
+		cal.line("TZID:" + name)
+		for _, k := range keys {
+			obs := zone.observances[k]
+			kind := "STANDARD"
+			if obs.dst {
+				kind = "DAYLIGHT"
+			}
+			cal.line("BEGIN:" + kind)
+			cal.line("DTSTART:" + obs.start.Format("20060102T150405"))
+			cal.line("TZOFFSETFROM:" + icalOffset(obs.offsetFrom))
+			cal.line("TZOFFSETTO:" + icalOffset(obs.offsetTo))
+			if obs.name != "" {
+				cal.line("TZNAME:" + icalEscape(obs.name))
+			}
+			cal.line("END:" + kind)
+		}
+		cal.line("END:VTIMEZONE")
+	}
+}
+
+// time format value DTSTART/DTEND: ";TZID=...:lokal" kalau timezone dikenal, selain itu UTC
+func (z *icalZones) time(name string, t time.Time) string {
+	if loc, ok := z.location(name); ok {
+		return ";TZID=" + name + ":" + t.In(loc).Format("20060102T150405")
+	}
+	return ":" + icalTime(t)
+}
+
+func icalOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	// 00:30 WIB 2 Maret = 17:30 UTC 1 Maret, di ICS harus tetap 2 Maret walau ada interview di New York
+	deadline := time.Date(2026, 3, 2, 0, 30, 0, 0, jakarta)
+	interviews := []model.Interview{
+		{
+			ID:          1,
+			RoundName:   "HR",
+			StartsAt:    time.Date(2026, 3, 10, 9, 0, 0, 0, newYork), // setelah DST mulai 8 Maret
+			EndsAt:      time.Date(2026, 3, 10, 10, 0, 0, 0, newYork),
+			Timezone:    "America/New_York",
+			MeetingLink: "https://meet.example.com/abc?x=1,2",
+		},
+		{
+			ID:          2,
+			RoundName:   "User",
+			StartsAt:    time.Date(2026, 3, 12, 14, 0, 0, 0, jakarta),
+			EndsAt:      time.Date(2026, 3, 12, 15, 0, 0, 0, jakarta),
+			Timezone:    "Asia/Jakarta",
+			MeetingLink: "zoom\r\nX-INJECTED:1",
+			Notes:       "bawa portofolio\rX-LONE-CR:1",
+		},
+	}
+	jobs := []model.Job{
+		{ID: 7, Title: "Go Dev", Company: "Acme", Deadline: &deadline, URL: "javascript:alert(1)"},
+	}
+
+	ics := writeCalendar(interviews, jobs, calendarLocation(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260308T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\n",
+		"TZID:Asia/Jakarta\r\n",
+		"DTSTART;TZID=America/New_York:20260310T090000\r\n",
+		"DTEND;TZID=America/New_York:20260310T100000\r\n",
+		"DTSTART;TZID=Asia/Jakarta:20260312T140000\r\n",
+		"URL:https://meet.example.com/abc?x=1,2\r\n",
+		"DTSTART;VALUE=DATE:20260302\r\nDTEND;VALUE=DATE:20260303\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("calendar missing %q\n%s", want, unfolded)
+		}
+	}
+
+	for _, unwanted := range []string{"\r\nX-INJECTED", "\rX-LONE-CR", "URL:zoom", "URL:javascript"} {
+		if strings.Contains(unfolded, unwanted) {
+			t.Errorf("calendar contains %q\n%s", unwanted, unfolded)
+		}
+	}
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 bytes: %q", line)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Kemiripan minimum (1 - levenshtein/panjang) supaya dua nama dianggap company yang sama.
+// Fuzzy cuma untuk nama yang cukup panjang: "bri" vs "bni" cuma beda satu huruf tapi company lain.
+const (
+	companyMatchThreshold = 0.85
+	companyFuzzyMinLength = 6
+)
+
+var ErrCompanyExists = errors.New("another company already uses this name")
+
+// Badan hukum / kata umum yang dibuang di awal atau akhir nama
+var companyAffixes = map[string]bool{
+	"pt": true, "tbk": true, "persero": true, "cv": true,
+	"inc": true, "ltd": true, "llc": true, "plc": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true,
+	"gmbh": true, "pte": true, "sdn": true, "bhd": true, "bv": true, "ag": true,
+}
+
+// Kata umum yang bukan pembeda company: "Bank BRI" dan "BRI" sama, "Bank BRI" dan "Bank BNI" beda
+var companyGenericWords = map[string]bool{
+	"bank": true, "group": true, "grup": true, "holding": true, "holdings": true,
+	"indonesia": true, "international": true, "internasional": true, "global": true,
+	"tech": true, "technology": true, "technologies": true, "teknologi": true,
+	"digital": true, "solutions": true, "solusi": true, "services": true, "labs": true,
+}
+
+// NormalizeCompanyName "PT. Tokopedia Tbk" → "tokopedia"
+func NormalizeCompanyName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for len(words) > 1 && companyAffixes[words[0]] {
+		words = words[1:]
+	}
+	for len(words) > 1 && companyAffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// companySimilarity bandingkan dua nama yang sudah dinormalisasi, spasi diabaikan ("go to" = "goto")
+func companySimilarity(a, b string) float64 {
+	ra := []rune(strings.ReplaceAll(a, " ", ""))
+	rb := []rune(strings.ReplaceAll(b, " ", ""))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// companyDistinctive bagian nama yang membedakan company, tanpa spasi ("bank bri" → "bri")
+func companyDistinctive(normalized string) string {
+	var words []string
+	for _, w := range strings.Fields(normalized) {
+		if !companyGenericWords[w] {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return strings.ReplaceAll(normalized, " ", "")
+	}
+	return strings.Join(words, "")
+}
+
+// companyMatchScore 1 kalau nama sama persis atau bagian pembedanya sama ("bank bri" = "bri"),
+// skor fuzzy kalau bagian pembedanya cukup panjang, selain itu 0. Input sudah dinormalisasi.
+func companyMatchScore(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if strings.ReplaceAll(a, " ", "") == strings.ReplaceAll(b, " ", "") {
+		return 1
+	}
+
+	da, db := companyDistinctive(a), companyDistinctive(b)
+	if da == db {
+		return 1
+	}
+	if len([]rune(da)) < companyFuzzyMinLength || len([]rune(db)) < companyFuzzyMinLength {
+		return 0
+	}
+	return companySimilarity(da, db)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// MatchCompany cari company user yang paling cocok dengan name (lihat companyMatchScore).
+// nil kalau tidak ada yang cukup mirip.
+func MatchCompany(userID uint, name string) (*model.Company, error) {
+	normalized := NormalizeCompanyName(name)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	var companies []model.Company
+	if err := database.DB.Where("user_id = ?", userID).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+
+	var best *model.Company
+	bestScore := 0.0
+	for i := range companies {
+		candidates := append([]string{companies[i].NormalizedName}, companies[i].Aliases...)
+		for _, candidate := range candidates {
+			score := companyMatchScore(normalized, NormalizeCompanyName(candidate))
+			if score > bestScore {
+				best, bestScore = &companies[i], score
+			}
+		}
+	}
+
+	if bestScore < companyMatchThreshold {
+		return nil, nil
+	}
+	return best, nil
+}
+
+// ResolveCompany ambil company yang cocok, atau buat baru. Ejaan baru disimpan sebagai alias
+// hanya kalau hasil normalisasinya sama persis; match fuzzy cuma dipakai untuk link.
+func ResolveCompany(userID uint, name string) (*model.Company, error) {
+	company, err := MatchCompany(userID, name)
+	if err != nil {
+		return nil, err
+	}
+	if company != nil {
+		if companyHasName(company, NormalizeCompanyName(name)) {
+			addCompanyAlias(company, name)
+		}
+		return company, nil
+	}
+
+	normalized := NormalizeCompanyName(name)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	company = &model.Company{
+		UserID:         userID,
+		Name:           strings.TrimSpace(name),
+		NormalizedName: normalized,
+	}
+	if err := database.DB.Create(company).Error; err != nil {
+		// Kemungkinan request lain barusan membuat company yang sama
+		var existing model.Company
+		if database.DB.Where("user_id = ? AND normalized_name = ?", userID, normalized).First(&existing).Error == nil {
+			return &existing, nil
+		}
+		return nil, err
+	}
+	return company, nil
+}
+
+// companyHasName true kalau nama (sudah dinormalisasi) sama persis dengan nama atau alias company
+func companyHasName(company *model.Company, normalized string) bool {
+	if normalized == "" {
+		return false
+	}
+	if company.NormalizedName == normalized {
+		return true
+	}
+	for _, alias := range company.Aliases {
+		if NormalizeCompanyName(alias) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+func addCompanyAlias(company *model.Company, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, company.Name) {
+		return
+	}
+	for _, alias := range company.Aliases {
+		if strings.EqualFold(alias, name) {
+			return
+		}
+	}
+
+	company.Aliases = append(company.Aliases, name)
+	database.DB.Model(company).Update("aliases", company.Aliases)
+}
+
+// LinkJobCompany hubungkan job ke company berdasarkan Job.Company
+func LinkJobCompany(job *model.Job) error {
+	company, err := ResolveCompany(job.UserID, job.Company)
+	if err != nil || company == nil {
+		return err
+	}
+
+	job.CompanyID = &company.ID
+	return database.DB.Model(job).UpdateColumn("company_id", company.ID).Error
+}
+
+// LinkJobCompanies hubungkan job hasil import yang belum punya company_id. Slice-nya tidak diubah,
+// jadi aman dipakai bareng goroutine lain.
+func LinkJobCompanies(jobs []model.Job) error {
+	for _, job := range jobs {
+		if job.CompanyID != nil {
+			continue
+		}
+		if err := LinkJobCompany(&job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillJobCompanies hubungkan job lama (dibuat sebelum ada tabel company) yang company_id-nya masih kosong
+func BackfillJobCompanies() error {
+	var batch []model.Job
+	return database.DB.Select("id", "user_id", "company").
+		Where("company_id IS NULL AND company <> ''").
+		FindInBatches(&batch, 200, func(tx *gorm.DB, _ int) error {
+			return LinkJobCompanies(batch)
+		}).Error
+}
+
+// MatchParsedCompany isi company_id hasil parse/scrape kalau company-nya sudah dikenal.
+// Namanya disamakan dengan nama tersimpan hanya kalau sama persis dengan nama/alias company,
+// match fuzzy tidak boleh mengganti nama yang ditulis di posting.
+func MatchParsedCompany(userID uint, parsed *ai.ParsedJob) {
+	parsed.CompanyID = nil
+
+	company, err := MatchCompany(userID, parsed.Company)
+	if err != nil || company == nil {
+		return
+	}
+
+	parsed.CompanyID = &company.ID
+	if companyHasName(company, NormalizeCompanyName(parsed.Company)) {
+		parsed.Company = company.Name
+	}
+}
+
+// RenameCompany ganti nama tampilan + alias. Nama lama otomatis jadi alias.
+func RenameCompany(company *model.Company, name string, aliases []string) error {
+	if name != "" && name != company.Name {
+		normalized := NormalizeCompanyName(name)
+		if normalized == "" {
+			return errors.New("name is empty after normalization")
+		}
+
+		var count int64
+		database.DB.Model(&model.Company{}).
+			Where("user_id = ? AND normalized_name = ? AND id <> ?", company.UserID, normalized, company.ID).
+			Count(&count)
+		if count > 0 {
+			return ErrCompanyExists
+		}
+
+		if aliases == nil {
+			aliases = company.Aliases
+		}
+		aliases = append(aliases, company.Name)
+		company.Name = strings.TrimSpace(name)
+		company.NormalizedName = normalized
+	}
+
+	if aliases != nil {
+		company.Aliases = dedupeAliases(company.Name, aliases)
+	}
+
+	return database.DB.Save(company).Error
+}
+
+// MergeCompanies pindahkan semua job dari source ke target, nama source jadi alias target
+func MergeCompanies(target, source *model.Company) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Job{}).Where("company_id = ?", source.ID).Update("company_id", target.ID).Error; err != nil {
+			return err
+		}
+
+		aliases := append(append([]string{}, target.Aliases...), source.Name)
+		target.Aliases = dedupeAliases(target.Name, append(aliases, source.Aliases...))
+		if err := tx.Save(target).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(source).Error
+	})
+}
+
+func dedupeAliases(name string, aliases []string) []string {
+	seen := map[string]bool{strings.ToLower(name): true}
+	out := []string{}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, alias)
+	}
+	return out
+}
+
+type CompanyStats struct {
+	model.Company
+	Applications    int      `json:"applications"`
+	Ghosted         int      `json:"ghosted"`
+	GhostRate       float64  `json:"ghost_rate"`
+	Responded       int      `json:"responded"`
+	AvgResponseDays *float64 `json:"avg_response_days"`
+}
+
+// BuildCompanyStats agregasi per company: jumlah lamaran, ghost rate, dan rata-rata hari sampai respon pertama.
+// companyID 0 = semua company milik user.
+func BuildCompanyStats(userID, companyID uint) ([]CompanyStats, error) {
+	query := database.DB.Where("user_id = ?", userID)
+	if companyID != 0 {
+		query = query.Where("id = ?", companyID)
+	}
+
+	var companies []model.Company
+	if err := query.Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	if len(companies) == 0 {
+		return []CompanyStats{}, nil
+	}
+
+	ids := make([]uint, len(companies))
+	for i, company := range companies {
+		ids[i] = company.ID
+	}
+
+	var jobs []model.Job
+	err := database.DB.
+		Where("user_id = ? AND company_id IN ?", userID, ids).
+		Preload("Timelines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("happened_at asc, id asc")
+		}).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byCompany := map[uint][]model.Job{}
+	for _, job := range jobs {
+		byCompany[*job.CompanyID] = append(byCompany[*job.CompanyID], job)
+	}
+
+	stats := make([]CompanyStats, 0, len(companies))
+	for _, company := range companies {
+		s := CompanyStats{Company: company}
+
+		var responseDays []float64
+		for _, job := range byCompany[company.ID] {
+			s.Applications++
+			if job.Status == model.StatusGhosted {
+				s.Ghosted++
+			}
+			if d, ok := firstResponseDays(job); ok {
+				responseDays = append(responseDays, d)
+			}
+		}
+
+		s.Responded = len(responseDays)
+		s.GhostRate = ratio(s.Ghosted, s.Applications)
+		if len(responseDays) > 0 {
+			total := 0.0
+			for _, d := range responseDays {
+				total += d
+			}
+			avg := round1(total / float64(len(responseDays)))
+			s.AvgResponseDays = &avg
+		}
+
+		stats = append(stats, s)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Applications != stats[j].Applications {
+			return stats[i].Applications > stats[j].Applications
+		}
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats, nil
+}
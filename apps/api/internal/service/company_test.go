@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/myfarism/lamarr-api/internal/model"
+)
+
+func TestNormalizeCompanyName(t *testing.T) {
+	tests := map[string]string{
+		"PT. Tokopedia Tbk":        "tokopedia",
+		"  Gojek  ":                "gojek",
+		"PT Bank Central Asia Tbk": "bank central asia",
+		"Grab Holdings Inc.":       "grab holdings",
+		"Company":                  "company",
+		"":                         "",
+	}
+	for input, want := range tests {
+		if got := NormalizeCompanyName(input); got != want {
+			t.Errorf("NormalizeCompanyName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCompanySimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{"tokopedia", "tokopedia", 1, 1},
+		{"go to", "goto", 1, 1},
+		{"tokopedia", "tokopedja", 0.85, 0.9},
+		{"gojek", "grab", 0, 0.5},
+		{"", "grab", 0, 0},
+	}
+	for _, tt := range tests {
+		got := companySimilarity(tt.a, tt.b)
+		if got < tt.min || got > tt.max {
+			t.Errorf("companySimilarity(%q, %q) = %.2f, want between %.2f and %.2f", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestCompanyMatchScore(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		match bool
+	}{
+		{"PT Tokopedia Tbk", "Tokopedia", true},
+		{"Go To", "GoTo", true},
+		{"Bank BRI", "BRI", true},
+		{"Bank BRI", "Bank BNI", false},
+		{"PT Bank Rakyat Indonesia", "Bank Negara Indonesia", false},
+		{"Tokopedia", "Tokopedja", true},
+		{"Gojek", "Gojak", false}, // terlalu pendek untuk fuzzy
+		{"Traveloka", "Tokopedia", false},
+		{"", "Grab", false},
+	}
+	for _, tt := range tests {
+		score := companyMatchScore(NormalizeCompanyName(tt.a), NormalizeCompanyName(tt.b))
+		if got := score >= companyMatchThreshold; got != tt.match {
+			t.Errorf("companyMatchScore(%q, %q) = %.2f, match %v, want %v", tt.a, tt.b, score, got, tt.match)
+		}
+	}
+}
+
+func TestCompanyHasName(t *testing.T) {
+	company := &model.Company{
+		Name:           "Tokopedia",
+		NormalizedName: "tokopedia",
+		Aliases:        pq.StringArray{"Tokped"},
+	}
+
+	for _, name := range []string{"PT Tokopedia Tbk", "tokopedia", "TOKPED"} {
+		if !companyHasName(company, NormalizeCompanyName(name)) {
+			t.Errorf("companyHasName(%q) = false, want true", name)
+		}
+	}
+	// Cuma mirip, jangan jadi alias
+	for _, name := range []string{"Tokopedja", "Toko pedia", ""} {
+		if companyHasName(company, NormalizeCompanyName(name)) {
+			t.Errorf("companyHasName(%q) = true, want false", name)
+		}
+	}
+}
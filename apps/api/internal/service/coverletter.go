@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LatestCoverLetter versi terakhir cover letter job, nil kalau belum ada
+func LatestCoverLetter(jobID uint) (*model.CoverLetter, error) {
+	var letter model.CoverLetter
+	err := database.DB.Where("job_id = ?", jobID).Order("version desc").First(&letter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &letter, nil
+}
+
+// GenerateCoverLetter tulis cover letter dari CV (versi yang dipakai job) + detail job,
+// lalu simpan sebagai versi baru. Opsi yang kosong mengikuti versi terakhir; bahasa fallback ke preferensi user.
+func GenerateCoverLetter(ctx context.Context, user model.User, job *model.Job, opts ai.CoverLetterOptions) (*model.CoverLetter, error) {
+	cvText, version := CVForJob(user, job)
+	if cvText == "" {
+		return nil, ErrNoCV
+	}
+
+	if previous, err := LatestCoverLetter(job.ID); err == nil && previous != nil {
+		if opts.Tone == "" {
+			opts.Tone = previous.Tone
+		}
+		if opts.Language == "" {
+			opts.Language = previous.Language
+		}
+		if opts.Length == "" {
+			opts.Length = previous.Length
+		}
+	}
+	opts.Language = OutputLanguage(user, opts.Language)
+	if err := opts.Normalize(); err != nil {
+		return nil, err
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Email
+	}
+
+	generated, err := ai.GenerateCoverLetter(ctx, ai.CoverLetterInput{
+		ApplicantName: name,
+		CvText:        cvText,
+		JobTitle:      job.Title,
+		Company:       job.Company,
+		Description:   job.Description,
+		Requirements:  job.Requirements,
+		Options:       opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	letter := &model.CoverLetter{
+		JobID:    job.ID,
+		UserID:   user.ID,
+		Content:  generated.Text,
+		Tone:     opts.Tone,
+		Language: opts.Language,
+		Length:   opts.Length,
+		Source:   model.CoverLetterSourceAI,
+
+		PromptVersion: generated.PromptVersion,
+	}
+	if version != nil {
+		letter.CvVersionID = &version.ID
+	}
+
+	return letter, SaveCoverLetterVersion(letter)
+}
+
+// SaveCoverLetterVersion simpan letter sebagai versi berikutnya untuk job-nya
+func SaveCoverLetterVersion(letter *model.CoverLetter) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		// Kunci job supaya dua generate bersamaan tidak dapat nomor versi yang sama
+		var job model.Job
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&job, letter.JobID).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&model.CoverLetter{}).Where("job_id = ?", letter.JobID).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		letter.Version = latest + 1
+		return tx.Create(letter).Error
+	})
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+var (
+	ErrUnsupportedCV = errors.New("unsupported CV file, upload a PDF or DOCX")
+	ErrEmptyCV       = errors.New("no text found in CV file")
+	ErrCVTooLarge    = errors.New("CV document is too large to read")
+)
+
+// Batas word/document.xml setelah didekompresi, supaya zip bomb tidak menghabiskan memori
+const maxDOCXDocumentSize = 8 << 20 // 8 MB
+
+// ExtractCVText ambil teks dari file CV (PDF/DOCX) lalu rapikan
+func ExtractCVText(fileName string, data []byte) (string, error) {
+	var (
+		text string
+		err  error
+	)
+
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		text, err = extractPDF(data)
+	case bytes.HasPrefix(data, []byte("PK")) && strings.EqualFold(filepath.Ext(fileName), ".docx"):
+		text, err = extractDOCX(data)
+	default:
+		return "", ErrUnsupportedCV
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = CleanCVText(text)
+	if text == "" {
+		return "", ErrEmptyCV
+	}
+	return text, nil
+}
+
+func extractPDF(data []byte) (text string, err error) {
+	// Library pdf bisa panic untuk file yang rusak
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to read PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	var b strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		writePDFText(&b, page.Content().Text)
+		b.WriteString("\n\n")
+	}
+
+	return b.String(), nil
+}
+
+// writePDFText susun glyph jadi baris berdasarkan posisi Y, dan sisipkan spasi
+// kalau jarak antar glyph cukup lebar (banyak PDF tidak menyimpan karakter spasi)
+func writePDFText(b *strings.Builder, glyphs []pdf.Text) {
+	var prev *pdf.Text
+	for i := range glyphs {
+		g := &glyphs[i]
+		if prev != nil {
+			size := math.Max(g.FontSize, 1)
+			switch {
+			case math.Abs(g.Y-prev.Y) > size*0.5:
+				b.WriteString("\n")
+			case g.X-(prev.X+prev.W) > size*0.2 && g.S != " " && prev.S != " ":
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(g.S)
+		prev = g
+	}
+}
+
+// extractDOCX baca word/document.xml: tiap <w:p> jadi satu baris
+func extractDOCX(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read DOCX: %w", err)
+	}
+
+	var document *zip.File
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			document = f
+			break
+		}
+	}
+	if document == nil {
+		return "", ErrUnsupportedCV
+	}
+
+	rc, err := document.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read DOCX: %w", err)
+	}
+	defer rc.Close()
+
+	// Baca satu byte lebih dari batas: kalau habis, file-nya kebesaran
+	limited := &io.LimitedReader{R: rc, N: maxDOCXDocumentSize + 1}
+	var b strings.Builder
+	decoder := xml.NewDecoder(limited)
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if limited.N == 0 {
+			return "", ErrCVTooLarge
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse DOCX: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteString("\t")
+			case "br", "cr":
+				b.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+
+	return b.String(), nil
+}
+
+var (
+	// "manage-\nment" → "management", hanya kalau huruf sesudahnya huruf kecil
+	hyphenBreak  = regexp.MustCompile(`(\p{L})-\n[ \t]*(\p{Ll})`)
+	inlineSpaces = regexp.MustCompile(`[ \t\x{00A0}]+`)
+	blankLines   = regexp.MustCompile(`\n{3,}`)
+	cvLigatures  = strings.NewReplacer("\u00ad", "", "\ufb01", "fi", "\ufb02", "fl", "\ufb00", "ff", "\r\n", "\n", "\r", "\n")
+)
+
+// CleanCVText rapikan whitespace dan sambung kata yang terpotong hyphenation
+func CleanCVText(text string) string {
+	text = cvLigatures.Replace(text)
+	text = hyphenBreak.ReplaceAllString(text, "$1$2")
+	text = inlineSpaces.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = strings.Join(lines, "\n")
+
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
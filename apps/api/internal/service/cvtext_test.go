@@ -0,0 +1,43 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func docxFile(t *testing.T, document string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(document)); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractDOCX(t *testing.T) {
+	doc := `<w:document><w:body><w:p><w:r><w:t>Go Developer</w:t></w:r></w:p><w:p><w:r><w:t>Jakarta</w:t></w:r></w:p></w:body></w:document>`
+	text, err := ExtractCVText("cv.docx", docxFile(t, doc))
+	if err != nil {
+		t.Fatalf("ExtractCVText: %v", err)
+	}
+	if !strings.Contains(text, "Go Developer") || !strings.Contains(text, "Jakarta") {
+		t.Errorf("text = %q", text)
+	}
+
+	// Kecil setelah dikompres, tapi lebih dari batas setelah didekompresi
+	bomb := "<w:document><w:body>" + strings.Repeat(" ", maxDOCXDocumentSize+1) + "</w:body></w:document>"
+	if _, err := ExtractCVText("cv.docx", docxFile(t, bomb)); !errors.Is(err, ErrCVTooLarge) {
+		t.Errorf("oversized document error = %v, want ErrCVTooLarge", err)
+	}
+}
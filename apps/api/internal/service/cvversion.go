@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// CVForJob teks CV untuk job: versi yang dicatat di job, atau CV default user kalau tidak ada
+func CVForJob(user model.User, job *model.Job) (string, *model.CvVersion) {
+	if job.CvVersionID != nil {
+		var version model.CvVersion
+		if database.DB.Where("id = ? AND user_id = ?", *job.CvVersionID, user.ID).First(&version).Error == nil {
+			return version.Text, &version
+		}
+	}
+	return user.CvText, nil
+}
+
+// DefaultCvVersion versi default user, nil kalau user belum punya versi sama sekali
+func DefaultCvVersion(userID uint) (*model.CvVersion, error) {
+	var version model.CvVersion
+	err := database.DB.Where("user_id = ? AND is_default = ?", userID, true).First(&version).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// BackfillDefaultCvVersions buat versi "Default" dari User.CvText untuk user lama yang belum
+// punya versi sama sekali. userID 0 = semua user.
+func BackfillDefaultCvVersions(userID uint) error {
+	query := `INSERT INTO cv_versions (user_id, label, text, is_default, created_at, updated_at)
+		SELECT u.id, 'Default', u.cv_text, true, now(), now()
+		FROM users u
+		WHERE u.cv_text <> ''
+			AND NOT EXISTS (SELECT 1 FROM cv_versions v WHERE v.user_id = u.id)`
+	if userID != 0 {
+		return database.DB.Exec(query+" AND u.id = ?", userID).Error
+	}
+	return database.DB.Exec(query).Error
+}
+
+// SaveDefaultCV dipakai PATCH /me/cv dan upload CV tanpa label:
+// timpa teks versi default (dibuat kalau belum ada) lalu salin ke User.CvText
+func SaveDefaultCV(userID uint, text string, fileID *uint) (*model.CvVersion, error) {
+	version, err := DefaultCvVersion(userID)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		version = &model.CvVersion{UserID: userID, Label: "Default", IsDefault: true}
+	}
+
+	version.Text = text
+	if fileID != nil {
+		version.CvFileID = fileID
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(version).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", userID).Update("cv_text", text).Error
+	})
+	return version, err
+}
+
+// SetDefaultCvVersion jadikan version satu-satunya default dan salin teksnya ke User.CvText
+func SetDefaultCvVersion(version *model.CvVersion) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.CvVersion{}).
+			Where("user_id = ? AND id <> ?", version.UserID, version.ID).
+			Update("is_default", false).Error
+		if err != nil {
+			return err
+		}
+
+		version.IsDefault = true
+		if err := tx.Save(version).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.User{}).Where("id = ?", version.UserID).Update("cv_text", version.Text).Error
+	})
+}
+
+// RefreshCvVersions embed semua versi CV user yang belum up to date
+func RefreshCvVersions(ctx context.Context, userID uint) error {
+	var versions []model.CvVersion
+	if err := database.DB.Where("user_id = ?", userID).Find(&versions).Error; err != nil {
+		return err
+	}
+
+	for i := range versions {
+		if versions[i].Text == "" {
+			continue
+		}
+		if _, err := EmbedCVVersion(ctx, &versions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CvVersionReport struct {
+	CvVersionID            *uint         `json:"cv_version_id"`
+	Label                  string        `json:"label"`
+	IsDefault              bool          `json:"is_default"`
+	Responded              int           `json:"responded"`
+	ResponseRate           float64       `json:"response_rate"`
+	AppliedToFirstResponse DurationStats `json:"applied_to_first_response"`
+	Funnel
+}
+
+// BuildCvVersionReport bandingkan response rate & funnel antar versi CV.
+// Job tanpa versi dikelompokkan dengan cv_version_id null.
+func BuildCvVersionReport(userID uint) ([]CvVersionReport, error) {
+	var versions []model.CvVersion
+	if err := database.DB.Where("user_id = ?", userID).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	var jobs []model.Job
+	err := database.DB.Where("user_id = ?", userID).
+		Preload("Timelines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("happened_at asc, id asc")
+		}).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := loadJobHistories(jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint][]jobHistory{}
+	for _, h := range histories {
+		var id uint
+		if h.job.CvVersionID != nil {
+			id = *h.job.CvVersionID
+		}
+		byVersion[id] = append(byVersion[id], h)
+	}
+
+	build := func(hs []jobHistory) CvVersionReport {
+		var responseDays []float64
+		for _, h := range hs {
+			if d, ok := firstResponseDays(h.job); ok {
+				responseDays = append(responseDays, d)
+			}
+		}
+		return CvVersionReport{
+			Responded:              len(responseDays),
+			ResponseRate:           ratio(len(responseDays), len(hs)),
+			AppliedToFirstResponse: durationStats(responseDays),
+			Funnel:                 buildFunnel(hs),
+		}
+	}
+
+	reports := []CvVersionReport{}
+	for _, version := range versions {
+		report := build(byVersion[version.ID])
+		id := version.ID
+		report.CvVersionID = &id
+		report.Label = version.Label
+		report.IsDefault = version.IsDefault
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Total > reports[j].Total
+	})
+
+	if hs := byVersion[0]; len(hs) > 0 {
+		report := build(hs)
+		report.Label = "Unassigned"
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
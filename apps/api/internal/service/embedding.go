@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm/clause"
+)
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// EmbedCV ambil embedding CV dari pgvector, atau hitung ulang kalau teks/model berubah
+func EmbedCV(ctx context.Context, userID uint, cvText string) (*ai.Embedding, error) {
+	e, err := ai.CurrentEmbedder()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := contentHash(cvText)
+
+	var stored model.CvEmbedding
+	err = database.DB.Where("user_id = ? AND model = ?", userID, e.ModelID()).First(&stored).Error
+	if err == nil && stored.ContentHash == hash {
+		return &ai.Embedding{Model: stored.Model, Vector: stored.Vector}, nil
+	}
+
+	embedding, err := ai.GetEmbedding(ctx, cvText)
+	if err != nil {
+		return nil, err
+	}
+
+	row := model.CvEmbedding{
+		UserID:      userID,
+		Model:       embedding.Model,
+		Dimension:   len(embedding.Vector),
+		ContentHash: hash,
+		Vector:      embedding.Vector,
+	}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "model"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dimension", "content_hash", "vector", "updated_at"}),
+	}).Create(&row).Error
+
+	return embedding, err
+}
+
+// EmbedCVVersion sama seperti EmbedCV tapi untuk satu CvVersion
+func EmbedCVVersion(ctx context.Context, version *model.CvVersion) (*ai.Embedding, error) {
+	e, err := ai.CurrentEmbedder()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := contentHash(version.Text)
+
+	var stored model.CvVersionEmbedding
+	err = database.DB.Where("cv_version_id = ? AND model = ?", version.ID, e.ModelID()).First(&stored).Error
+	if err == nil && stored.ContentHash == hash {
+		return &ai.Embedding{Model: stored.Model, Vector: stored.Vector}, nil
+	}
+
+	embedding, err := ai.GetEmbedding(ctx, version.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	row := model.CvVersionEmbedding{
+		CvVersionID: version.ID,
+		UserID:      version.UserID,
+		Model:       embedding.Model,
+		Dimension:   len(embedding.Vector),
+		ContentHash: hash,
+		Vector:      embedding.Vector,
+	}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cv_version_id"}, {Name: "model"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dimension", "content_hash", "vector", "updated_at"}),
+	}).Create(&row).Error
+
+	return embedding, err
+}
+
+// EmbedJobField sama seperti EmbedCV tapi untuk satu field job
+func EmbedJobField(ctx context.Context, job *model.Job, field string) (*ai.Embedding, error) {
+	e, err := ai.CurrentEmbedder()
+	if err != nil {
+		return nil, err
+	}
+
+	text := jobFieldText(job, field)
+	hash := contentHash(text)
+
+	var stored model.JobEmbedding
+	err = database.DB.
+		Where("job_id = ? AND field = ? AND model = ?", job.ID, field, e.ModelID()).
+		First(&stored).Error
+	if err == nil && stored.ContentHash == hash {
+		return &ai.Embedding{Model: stored.Model, Vector: stored.Vector}, nil
+	}
+
+	embedding, err := ai.GetEmbedding(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	row := model.JobEmbedding{
+		JobID:       job.ID,
+		UserID:      job.UserID,
+		Field:       field,
+		Model:       embedding.Model,
+		Dimension:   len(embedding.Vector),
+		ContentHash: hash,
+		Vector:      embedding.Vector,
+	}
+	err = database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_id"}, {Name: "field"}, {Name: "model"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dimension", "content_hash", "vector", "updated_at"}),
+	}).Create(&row).Error
+
+	return embedding, err
+}
+
+func jobFieldText(job *model.Job, field string) string {
+	if field == model.EmbeddingFieldDescription {
+		return job.Description
+	}
+	return job.Requirements
+}
+
+// RefreshCV dipanggil tiap CV berubah: simpan embedding baru lalu update match score semua job
+func RefreshCV(ctx context.Context, userID uint, cvText string) error {
+	if strings.TrimSpace(cvText) == "" {
+		return database.DB.Where("user_id = ?", userID).Delete(&model.CvEmbedding{}).Error
+	}
+
+	if _, err := EmbedCV(ctx, userID, cvText); err != nil {
+		return err
+	}
+	return RescoreJobs(userID)
+}
+
+// RefreshCVVersion dipanggil tiap teks CvVersion berubah
+func RefreshCVVersion(ctx context.Context, version *model.CvVersion) error {
+	if strings.TrimSpace(version.Text) == "" {
+		return database.DB.Where("cv_version_id = ?", version.ID).Delete(&model.CvVersionEmbedding{}).Error
+	}
+
+	if _, err := EmbedCVVersion(ctx, version); err != nil {
+		return err
+	}
+	return RescoreJobs(version.UserID)
+}
+
+// RefreshJob dipanggil tiap description/requirements job berubah
+func RefreshJob(ctx context.Context, job *model.Job) error {
+	for _, field := range []string{model.EmbeddingFieldRequirements, model.EmbeddingFieldDescription} {
+		if strings.TrimSpace(jobFieldText(job, field)) == "" {
+			database.DB.Where("job_id = ? AND field = ?", job.ID, field).Delete(&model.JobEmbedding{})
+			continue
+		}
+		if _, err := EmbedJobField(ctx, job, field); err != nil {
+			return err
+		}
+	}
+	return RescoreJobs(job.UserID)
+}
+
+// RescoreJobs hitung ulang match_score langsung dari vector tersimpan (tanpa panggil API embedding).
+// Job yang mencatat CvVersion dibandingkan dengan versi itu, sisanya dengan CV default.
+// Hanya membandingkan vector dari model yang sama.
+func RescoreJobs(userID uint) error {
+	e, err := ai.CurrentEmbedder()
+	if err != nil {
+		return err
+	}
+
+	err = database.DB.Exec(`
+		UPDATE jobs SET match_score = 1 - (je.vector <=> ce.vector)
+		FROM job_embeddings je, cv_embeddings ce
+		WHERE je.job_id = jobs.id
+		  AND je.field = ?
+		  AND ce.user_id = jobs.user_id
+		  AND je.model = ce.model
+		  AND je.model = ?
+		  AND jobs.user_id = ?
+		  AND jobs.cv_version_id IS NULL`,
+		model.EmbeddingFieldRequirements, e.ModelID(), userID,
+	).Error
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Exec(`
+		UPDATE jobs SET match_score = 1 - (je.vector <=> ve.vector)
+		FROM job_embeddings je, cv_version_embeddings ve
+		WHERE je.job_id = jobs.id
+		  AND je.field = ?
+		  AND ve.cv_version_id = jobs.cv_version_id
+		  AND je.model = ve.model
+		  AND je.model = ?
+		  AND jobs.user_id = ?`,
+		model.EmbeddingFieldRequirements, e.ModelID(), userID,
+	).Error
+}
+
+// InBackground jalankan refresh embedding tanpa nahan HTTP response
+func InBackground(name string, fn func(ctx context.Context) error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := fn(ctx); err != nil {
+			log.Printf("%s failed: %v", name, err)
+		}
+	}()
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+type staleJob struct {
+	model.Job
+	GhostAfterDays int
+	LastActivity   time.Time
+}
+
+// SweepGhostedJobs pindahkan job applied/screening yang tidak ada aktivitas timeline
+// lebih lama dari threshold user ke ghosted. User yang opt-out dilewati.
+func SweepGhostedJobs(ctx context.Context) (int, error) {
+	var stale []staleJob
+	err := database.DB.WithContext(ctx).Raw(`
+		SELECT * FROM (
+			SELECT jobs.*, users.ghost_after_days,
+				COALESCE((SELECT MAX(t.happened_at) FROM job_timelines t WHERE t.job_id = jobs.id), jobs.applied_at) AS last_activity
+			FROM jobs
+			JOIN users ON users.id = jobs.user_id
+			WHERE users.auto_ghost_enabled
+			  AND jobs.deleted_at IS NULL
+			  AND jobs.status IN ?
+		) candidates
+		WHERE last_activity < NOW() - make_interval(days => ghost_after_days)`,
+		[]model.JobStatus{model.StatusApplied, model.StatusScreening},
+	).Scan(&stale).Error
+	if err != nil {
+		return 0, err
+	}
+
+	ghosted := 0
+	for _, job := range stale {
+		updated := false
+		err := database.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Cek status lagi di dalam transaksi, siapa tau user baru saja update
+			res := tx.Model(&model.Job{}).
+				Where("id = ? AND status = ?", job.ID, job.Status).
+				Update("status", model.StatusGhosted)
+			if res.Error != nil || res.RowsAffected == 0 {
+				return res.Error
+			}
+			updated = true
+
+			days := int(time.Since(job.LastActivity).Hours() / 24)
+			return tx.Create(&model.JobTimeline{
+				JobID: job.ID,
+				Stage: string(model.StatusGhosted),
+				Note: fmt.Sprintf(
+					"Automatically marked as ghosted: no activity for %d days while %s (threshold %d days)",
+					days, job.Status, job.GhostAfterDays,
+				),
+				HappenedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return ghosted, err
+		}
+		if updated {
+			ghosted++
+		}
+	}
+
+	return ghosted, nil
+}
+
+// StartGhostSweeper sweep pakai ticker di proses server. scheduled (boleh nil) dicek tiap tick:
+// kalau worker sudah menjadwalkan sweep, tick itu dilewati; kalau tidak, sweep jalan di sini
+// dan perpindahannya dicatat di log supaya sweep tidak diam-diam berhenti.
+func StartGhostSweeper(interval time.Duration, scheduled func() bool) {
+	go func() {
+		// Kasih waktu worker yang start bareng server untuk mendaftarkan jadwalnya
+		time.Sleep(time.Minute)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		inServer := false
+		for ; ; <-ticker.C {
+			if scheduled != nil {
+				byWorker := scheduled()
+				if !byWorker && !inServer {
+					log.Println("⚠️  no worker has scheduled the ghost sweep, running it in the server process")
+				} else if byWorker && inServer {
+					log.Println("ghost sweep is scheduled by a worker again, server sweep paused")
+				}
+				inServer = !byWorker
+				if byWorker {
+					continue
+				}
+			}
+
+			n, err := SweepGhostedJobs(context.Background())
+			if err != nil {
+				log.Printf("ghost sweep failed: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("👻 ghost sweep: %d jobs marked as ghosted", n)
+			}
+		}
+	}()
+}
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ImportCandidate = satu job hasil parse file import, belum disimpan
+type ImportCandidate struct {
+	Ref       string // nomor baris / ID kartu, buat laporan
+	Job       model.Job
+	Timelines []model.JobTimeline
+}
+
+type ImportIssue struct {
+	Ref     string `json:"ref"`
+	Message string `json:"message"`
+}
+
+type ImportDuplicate struct {
+	Ref           string `json:"ref"`
+	Title         string `json:"title"`
+	Company       string `json:"company"`
+	Reason        string `json:"reason"`
+	ExistingJobID *uint  `json:"existing_job_id,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun     bool              `json:"dry_run"`
+	Total      int               `json:"total"`
+	Valid      int               `json:"valid"`
+	Created    int               `json:"created"`
+	Duplicates []ImportDuplicate `json:"duplicates"`
+	Errors     []ImportIssue     `json:"errors"`
+	Jobs       []model.Job       `json:"jobs"`
+}
+
+// CSVMapping — kolom CSV mana yang masuk ke field Job mana
+type CSVMapping struct {
+	Columns      map[string]string `json:"columns"`       // field → nama kolom, misal "title": "Position"
+	StatusValues map[string]string `json:"status_values"` // nilai di CSV → JobStatus, misal "Phone Screen": "screening"
+	DateFormat   string            `json:"date_format"`   // layout Go, opsional
+	Platform     string            `json:"platform"`      // default platform kalau kolom kosong
+}
+
+var importFields = map[string]bool{
+	"title": true, "company": true, "status": true, "status_changed_at": true,
+	"url": true, "platform": true, "description": true, "requirements": true,
+	"notes": true, "salary_min": true, "salary_max": true,
+	"salary_currency": true, "salary_period": true, "salary_type": true,
+	"applied_at": true, "deadline": true,
+}
+
+// Kolom "Job Applications" dari LinkedIn data export
+var LinkedInMapping = CSVMapping{
+	Columns: map[string]string{
+		"title":      "Job Title",
+		"company":    "Company Name",
+		"url":        "Job Url",
+		"applied_at": "Application Date",
+	},
+	DateFormat: "1/2/06, 3:04 PM",
+	Platform:   "linkedin",
+}
+
+// Sinonim status yang sering muncul di spreadsheet orang
+var statusSynonyms = map[string]model.JobStatus{
+	"applied":         model.StatusApplied,
+	"submitted":       model.StatusApplied,
+	"sent":            model.StatusApplied,
+	"screening":       model.StatusScreening,
+	"phone screen":    model.StatusScreening,
+	"hr screen":       model.StatusScreening,
+	"interview":       model.StatusInterview,
+	"interviewing":    model.StatusInterview,
+	"offer":           model.StatusOffer,
+	"offered":         model.StatusOffer,
+	"rejected":        model.StatusRejected,
+	"declined":        model.StatusRejected,
+	"ditolak":         model.StatusRejected,
+	"ghosted":         model.StatusGhosted,
+	"no response":     model.StatusGhosted,
+	"tidak ada kabar": model.StatusGhosted,
+}
+
+var importDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"02/01/2006", // format Indonesia dd/mm/yyyy
+	"2-1-2006",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+	"January 2, 2006",
+	"1/2/06, 3:04 PM",
+}
+
+func (m CSVMapping) Validate() error {
+	for field := range m.Columns {
+		if !importFields[field] {
+			return fmt.Errorf("unknown field %q in mapping", field)
+		}
+	}
+	if m.Columns["title"] == "" || m.Columns["company"] == "" {
+		return fmt.Errorf("mapping must include title and company columns")
+	}
+	for raw, status := range m.StatusValues {
+		if !model.JobStatus(status).IsValid() {
+			return fmt.Errorf("status_values[%q]: invalid status %q", raw, status)
+		}
+	}
+	return nil
+}
+
+// ParseCSV baca file CSV jadi kandidat job. Baris yang tidak valid masuk ke issues, tidak menggagalkan semuanya.
+func ParseCSV(r io.Reader, mapping CSVMapping, source string) ([]ImportCandidate, []ImportIssue, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, col := range header {
+		col = strings.TrimSpace(strings.TrimPrefix(col, "\ufeff"))
+		index[strings.ToLower(col)] = i
+	}
+	for field, col := range mapping.Columns {
+		if _, ok := index[strings.ToLower(col)]; !ok {
+			return nil, nil, fmt.Errorf("column %q (for %s) not found in CSV", col, field)
+		}
+	}
+
+	var candidates []ImportCandidate
+	var issues []ImportIssue
+
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		ref := fmt.Sprintf("line %d", line)
+		if err != nil {
+			issues = append(issues, ImportIssue{Ref: ref, Message: err.Error()})
+			continue
+		}
+
+		get := func(field string) string {
+			col, ok := mapping.Columns[field]
+			if !ok {
+				return ""
+			}
+			i := index[strings.ToLower(col)]
+			if i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		candidate, err := csvCandidate(get, mapping, source)
+		if err != nil {
+			issues = append(issues, ImportIssue{Ref: ref, Message: err.Error()})
+			continue
+		}
+		candidate.Ref = ref
+		candidates = append(candidates, *candidate)
+	}
+
+	return candidates, issues, nil
+}
+
+func csvCandidate(get func(string) string, mapping CSVMapping, source string) (*ImportCandidate, error) {
+	job := model.Job{
+		Title:        get("title"),
+		Company:      get("company"),
+		URL:          get("url"),
+		Platform:     strings.ToLower(get("platform")),
+		Description:  get("description"),
+		Requirements: get("requirements"),
+		Notes:        get("notes"),
+		Status:       model.StatusApplied,
+	}
+
+	if job.Title == "" {
+		return nil, fmt.Errorf("title is empty")
+	}
+	if job.Company == "" {
+		return nil, fmt.Errorf("company is empty")
+	}
+	if job.Platform == "" {
+		job.Platform = mapping.Platform
+	}
+
+	if raw := get("status"); raw != "" {
+		status, err := parseImportStatus(raw, mapping.StatusValues)
+		if err != nil {
+			return nil, err
+		}
+		job.Status = status
+	}
+
+	// Currency dibaca dulu karena menentukan pemisah ribuan/desimal angka gaji
+	job.SalaryCurrency = get("salary_currency")
+	currency := job.SalaryCurrency
+	if currency == "" {
+		currency = BaseCurrency()
+	}
+	var err error
+	var minCurrency, maxCurrency string
+	if job.SalaryMin, minCurrency, err = parseImportAmount(get("salary_min"), currency); err != nil {
+		return nil, fmt.Errorf("salary_min: %w", err)
+	}
+	if job.SalaryMax, maxCurrency, err = parseImportAmount(get("salary_max"), currency); err != nil {
+		return nil, fmt.Errorf("salary_max: %w", err)
+	}
+	// Tanpa kolom currency, simbol di angka ("$5,000") yang dipakai
+	if job.SalaryCurrency == "" {
+		job.SalaryCurrency = minCurrency
+		if job.SalaryCurrency == "" {
+			job.SalaryCurrency = maxCurrency
+		}
+	}
+	job.SalaryPeriod = get("salary_period")
+	job.SalaryType = get("salary_type")
+	if err := NormalizeSalaryFields(&job); err != nil {
+		return nil, err
+	}
+
+	job.AppliedAt = time.Now()
+	if raw := get("applied_at"); raw != "" {
+		if job.AppliedAt, err = parseImportDate(raw, mapping.DateFormat); err != nil {
+			return nil, fmt.Errorf("applied_at: %w", err)
+		}
+	}
+	if raw := get("deadline"); raw != "" {
+		deadline, err := parseImportDate(raw, mapping.DateFormat)
+		if err != nil {
+			return nil, fmt.Errorf("deadline: %w", err)
+		}
+		job.Deadline = &deadline
+	}
+
+	candidate := &ImportCandidate{Job: job}
+	note := "Imported from " + source
+	if job.Status != model.StatusApplied {
+		note += " (status: " + string(job.Status) + ")"
+	}
+	candidate.Timelines = append(candidate.Timelines, model.JobTimeline{
+		Stage:      string(model.StatusApplied),
+		Note:       note,
+		HappenedAt: job.AppliedAt,
+	})
+
+	// Tanpa tanggal perubahan status, jangan bikin entry timeline palsu — bikin metrik latency kacau
+	if raw := get("status_changed_at"); raw != "" && job.Status != model.StatusApplied {
+		changedAt, err := parseImportDate(raw, mapping.DateFormat)
+		if err != nil {
+			return nil, fmt.Errorf("status_changed_at: %w", err)
+		}
+		candidate.Timelines = append(candidate.Timelines, model.JobTimeline{
+			Stage:      string(job.Status),
+			Note:       "Imported from " + source,
+			HappenedAt: changedAt,
+		})
+	}
+
+	return candidate, nil
+}
+
+func parseImportStatus(raw string, custom map[string]string) (model.JobStatus, error) {
+	for k, v := range custom {
+		if strings.EqualFold(strings.TrimSpace(k), raw) {
+			return model.JobStatus(v), nil
+		}
+	}
+	if status, ok := statusSynonyms[strings.ToLower(raw)]; ok {
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown status %q, add it to status_values", raw)
+}
+
+func parseImportDate(raw, layout string) (time.Time, error) {
+	if layout != "" {
+		return time.ParseInLocation(layout, raw, time.Local)
+	}
+	for _, l := range importDateLayouts {
+		if t, err := time.ParseInLocation(l, raw, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", raw)
+}
+
+// Currency yang menulis ribuan pakai titik dan desimal pakai koma ("15.000.000,00").
+// Currency lain (USD, EUR, SGD, ...) pakai koma untuk ribuan dan titik untuk desimal ("5,500.50").
+var dotThousandsCurrencies = map[string]bool{"IDR": true, "VND": true}
+
+var (
+	importCommaThousands = regexp.MustCompile(`^(\d+|\d{1,3}([, ]\d{3})+)(\.\d+)?$`)
+	importDotThousands   = regexp.MustCompile(`^(\d+|\d{1,3}([. ]\d{3})+)(,\d+)?$`)
+)
+
+// Simbol/kode mata uang yang boleh menempel di angka: "Rp 15.000.000", "$5,000", "15000000 IDR"
+var importCurrencyAffix = regexp.MustCompile(`(?i)^(rp\.?|[a-z]{3}|[$€£¥])?\s*(.*?)\s*(rp\.?|[a-z]{3}|[$€£¥])?$`)
+
+var importCurrencySymbols = map[string]string{"rp": "IDR", "rp.": "IDR", "$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY"}
+
+// Pemisah yang muncul lebih dari sekali pasti pemisah ribuan, apa pun currency-nya
+var importRepeatedThousands = regexp.MustCompile(`^\d{1,3}((,\d{3}){2,}|(\.\d{3}){2,}|( \d{3}){2,})$`)
+
+func importCurrency(affix string) string {
+	affix = strings.ToLower(affix)
+	if currency, ok := importCurrencySymbols[affix]; ok {
+		return currency
+	}
+	return strings.ToUpper(affix)
+}
+
+// parseImportAmount baca angka gaji. Pemisah ribuan/desimal ditentukan currency (dari simbol di angka,
+// atau currency kolom), jadi "5.500" = 5500 untuk IDR tapi 5.5 untuk USD. Desimal dibulatkan ke satuan.
+// Range ("10-15jt") atau singkatan ("15jt") ditolak supaya tidak diam-diam jadi angka lain.
+// Currency dari simbol/kode ikut dikembalikan ("" kalau tidak ada).
+func parseImportAmount(raw, currency string) (*int, string, error) {
+	raw = strings.TrimSpace(strings.ReplaceAll(raw, "\u00a0", " "))
+	if raw == "" {
+		return nil, "", nil
+	}
+
+	m := importCurrencyAffix.FindStringSubmatch(raw)
+	number, affix := m[2], importCurrency(m[1])
+	if affix == "" {
+		affix = importCurrency(m[3])
+	}
+	if affix != "" {
+		currency = affix
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+
+	pattern, thousands, decimal := importCommaThousands, ",", "."
+	if dotThousandsCurrencies[currency] {
+		pattern, thousands, decimal = importDotThousands, ".", ","
+	}
+
+	intPart, fraction := number, ""
+	if !importRepeatedThousands.MatchString(number) {
+		sub := pattern.FindStringSubmatch(number)
+		if sub == nil {
+			return nil, "", fmt.Errorf("invalid number %q, use a single amount with %q for thousands and %q for decimals (%s)",
+				raw, thousands, decimal, currency)
+		}
+		intPart, fraction = sub[1], strings.TrimPrefix(sub[3], decimal)
+	}
+
+	// Pemisah ribuan harus konsisten, "1.000 000" ditolak
+	separators := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return -1
+		}
+		return r
+	}, intPart)
+	if separators != "" && strings.Trim(separators, separators[:1]) != "" {
+		return nil, "", fmt.Errorf("invalid number %q, mixed thousands separators", raw)
+	}
+
+	v, err := strconv.Atoi(strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, intPart))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid number %q: %w", raw, err)
+	}
+	if fraction != "" {
+		f, _ := strconv.ParseFloat("0."+fraction, 64)
+		v += int(math.Round(f))
+	}
+	return &v, affix, nil
+}
+
+func duplicateKey(title, company string) string {
+	return strings.ToLower(strings.TrimSpace(company)) + "|" + strings.ToLower(strings.TrimSpace(title))
+}
+
+// splitDuplicates pisahkan kandidat yang duplikat dengan job yang sudah ada (URL sama, atau title+company sama)
+// maupun dengan baris sebelumnya di file yang sama
+func splitDuplicates(candidates []ImportCandidate, existing []model.Job) ([]ImportCandidate, []ImportDuplicate) {
+	byKey := map[string]uint{}
+	byURL := map[string]uint{}
+	for _, job := range existing {
+		byKey[duplicateKey(job.Title, job.Company)] = job.ID
+		if job.URL != "" {
+			byURL[job.URL] = job.ID
+		}
+	}
+
+	seen := map[string]string{}
+	var accepted []ImportCandidate
+	var duplicates []ImportDuplicate
+	for _, cand := range candidates {
+		key := duplicateKey(cand.Job.Title, cand.Job.Company)
+		dup := ImportDuplicate{Ref: cand.Ref, Title: cand.Job.Title, Company: cand.Job.Company}
+
+		if id, ok := byURL[cand.Job.URL]; ok && cand.Job.URL != "" {
+			dup.Reason = "same URL as an existing job"
+			dup.ExistingJobID = &id
+		} else if id, ok := byKey[key]; ok {
+			dup.Reason = "same title and company as an existing job"
+			dup.ExistingJobID = &id
+		} else if ref, ok := seen[key]; ok {
+			dup.Reason = "duplicate of " + ref + " in this file"
+		}
+
+		if dup.Reason != "" {
+			duplicates = append(duplicates, dup)
+			continue
+		}
+
+		seen[key] = cand.Ref
+		accepted = append(accepted, cand)
+	}
+	return accepted, duplicates
+}
+
+// CommitImport cek duplikat (ke job yang sudah ada dan sesama isi file), lalu simpan kalau bukan dry run.
+// Semua job disimpan dalam satu transaksi.
+func CommitImport(userID uint, candidates []ImportCandidate, issues []ImportIssue, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{
+		DryRun:     dryRun,
+		Total:      len(candidates) + len(issues),
+		Duplicates: []ImportDuplicate{},
+		Errors:     issues,
+		Jobs:       []model.Job{},
+	}
+	if result.Errors == nil {
+		result.Errors = []ImportIssue{}
+	}
+
+	var existing []model.Job
+	if err := database.DB.Select("id", "title", "company", "url").Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	rates, err := ExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+	accepted, duplicates := splitDuplicates(candidates, existing)
+	result.Duplicates = append(result.Duplicates, duplicates...)
+	for i := range accepted {
+		accepted[i].Job.UserID = userID
+		ApplySalaryNormalization(&accepted[i].Job, rates)
+	}
+	result.Valid = len(accepted)
+
+	if dryRun {
+		for _, cand := range accepted {
+			result.Jobs = append(result.Jobs, cand.Job)
+		}
+		return result, nil
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, cand := range accepted {
+			job := cand.Job
+			if err := tx.Create(&job).Error; err != nil {
+				return fmt.Errorf("%s: %w", cand.Ref, err)
+			}
+			for _, t := range cand.Timelines {
+				t.JobID = job.ID
+				if err := tx.Create(&t).Error; err != nil {
+					return fmt.Errorf("%s: %w", cand.Ref, err)
+				}
+			}
+			result.Jobs = append(result.Jobs, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Created = len(result.Jobs)
+	return result, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+)
+
+func TestParseImportAmount(t *testing.T) {
+	tests := []struct {
+		raw          string
+		currency     string
+		want         *int
+		wantCurrency string
+		wantErr      bool
+	}{
+		{raw: "", currency: "IDR", want: nil},
+		{raw: "15000000", currency: "IDR", want: intPtr(15000000)},
+		{raw: "15.000.000", currency: "IDR", want: intPtr(15000000)},
+		{raw: "15,000,000", currency: "IDR", want: intPtr(15000000)},
+		{raw: "15 000 000", currency: "IDR", want: intPtr(15000000)},
+		{raw: "Rp 15.000.000", currency: "IDR", want: intPtr(15000000), wantCurrency: "IDR"},
+		{raw: "Rp. 8.500.000", currency: "IDR", want: intPtr(8500000), wantCurrency: "IDR"},
+		{raw: "15000000 IDR", currency: "IDR", want: intPtr(15000000), wantCurrency: "IDR"},
+		{raw: "15.000.000,00", currency: "IDR", want: intPtr(15000000)},
+		// Satu titik: ribuan untuk IDR, desimal untuk USD/EUR
+		{raw: "5.500", currency: "IDR", want: intPtr(5500)},
+		{raw: "5.500", currency: "USD", want: intPtr(6)},
+		{raw: "5.500", currency: "EUR", want: intPtr(6)},
+		{raw: "5,500", currency: "USD", want: intPtr(5500)},
+		{raw: "5,5", currency: "IDR", want: intPtr(6)},
+		{raw: "$5,000", currency: "IDR", want: intPtr(5000), wantCurrency: "USD"},
+		{raw: "€ 4.500", currency: "IDR", want: intPtr(5), wantCurrency: "EUR"},
+		{raw: "120,000.40", currency: "USD", want: intPtr(120000)},
+		{raw: "1.000.000", currency: "USD", want: intPtr(1000000)},
+		{raw: "10-15jt", currency: "IDR", wantErr: true},
+		{raw: "15jt", currency: "IDR", wantErr: true},
+		{raw: "5.5", currency: "IDR", wantErr: true},
+		{raw: "15,000.50", currency: "IDR", wantErr: true},
+		{raw: "15.000,50", currency: "USD", wantErr: true},
+		{raw: "1,000 000", currency: "USD", wantErr: true},
+		{raw: "negotiable", currency: "IDR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, currency, err := parseImportAmount(tt.raw, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseImportAmount(%q, %s) = %v, want error", tt.raw, tt.currency, deref(got))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseImportAmount(%q, %s) error: %v", tt.raw, tt.currency, err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) || currency != tt.wantCurrency {
+			t.Errorf("parseImportAmount(%q, %s) = %v %q, want %v %q", tt.raw, tt.currency, deref(got), currency, deref(tt.want), tt.wantCurrency)
+		}
+	}
+}
+
+func TestParseCSVGenericMapping(t *testing.T) {
+	csv := "Position,Company,Stage,Applied,Pay,Currency\n" +
+		"Backend Engineer,Tokopedia,Phone Screen,2026-01-05,\"Rp 15.000.000\",\n" +
+		"Data Engineer,Gojek,Offer,2026-02-10,\"$5,000\",usd\n" +
+		",Missing Title,applied,2026-01-01,,\n" +
+		"Go Developer,Xendit,applied,2026-03-01,10-15jt,\n"
+
+	mapping := CSVMapping{
+		Columns: map[string]string{
+			"title":           "Position",
+			"company":         "Company",
+			"status":          "Stage",
+			"applied_at":      "Applied",
+			"salary_min":      "Pay",
+			"salary_currency": "Currency",
+		},
+		StatusValues: map[string]string{"Offer": "offer"},
+		Platform:     "other",
+	}
+
+	candidates, issues, err := ParseCSV(strings.NewReader(csv), mapping, "test CSV")
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("got %d candidates, want 2 (issues: %+v)", len(candidates), issues)
+	}
+	if len(issues) != 2 || issues[0].Ref != "line 4" || issues[1].Ref != "line 5" {
+		t.Fatalf("issues = %+v, want line 4 (no title) and line 5 (salary range)", issues)
+	}
+	if !strings.Contains(issues[1].Message, "salary_min") {
+		t.Errorf("salary issue message = %q, want it to name salary_min", issues[1].Message)
+	}
+
+	first := candidates[0].Job
+	if first.Status != model.StatusScreening || first.Platform != "other" {
+		t.Errorf("first job status/platform = %s/%s, want screening/other", first.Status, first.Platform)
+	}
+	if deref(first.SalaryMin) != 15000000 || first.SalaryCurrency != "IDR" || first.SalaryPeriod != model.SalaryPeriodMonth {
+		t.Errorf("first job salary = %v %s/%s", deref(first.SalaryMin), first.SalaryCurrency, first.SalaryPeriod)
+	}
+	if got := first.AppliedAt.Format("2006-01-02"); got != "2026-01-05" {
+		t.Errorf("first job applied_at = %s", got)
+	}
+
+	second := candidates[1]
+	if second.Job.Status != model.StatusOffer || second.Job.SalaryCurrency != "USD" {
+		t.Errorf("second job status/currency = %s/%s, want offer/USD", second.Job.Status, second.Job.SalaryCurrency)
+	}
+	// Tanpa status_changed_at cuma ada entry applied, tidak ada timeline palsu
+	if len(second.Timelines) != 1 || second.Timelines[0].Stage != string(model.StatusApplied) {
+		t.Errorf("second job timelines = %+v, want only the applied entry", second.Timelines)
+	}
+}
+
+func TestParseCSVLinkedInExport(t *testing.T) {
+	csv := "\ufeffApplication Date,Contact Email,Contact Phone Number,Company Name,Job Title,Job Url,Resume Name,Question And Answers\n" +
+		"\"3/14/26, 9:05 AM\",me@example.com,,Tokopedia,Software Engineer,https://www.linkedin.com/jobs/view/123,cv.pdf,\n"
+
+	candidates, issues, err := ParseCSV(strings.NewReader(csv), LinkedInMapping, "LinkedIn CSV")
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	if len(issues) != 0 || len(candidates) != 1 {
+		t.Fatalf("got %d candidates, issues %+v", len(candidates), issues)
+	}
+
+	job := candidates[0].Job
+	if job.Title != "Software Engineer" || job.Company != "Tokopedia" || job.Platform != "linkedin" {
+		t.Errorf("job = %q at %q on %q", job.Title, job.Company, job.Platform)
+	}
+	if job.URL != "https://www.linkedin.com/jobs/view/123" {
+		t.Errorf("job url = %q", job.URL)
+	}
+	want := time.Date(2026, 3, 14, 9, 5, 0, 0, time.Local)
+	if !job.AppliedAt.Equal(want) {
+		t.Errorf("applied_at = %s, want %s", job.AppliedAt, want)
+	}
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	_, _, err := ParseCSV(strings.NewReader("Title\nGo Dev\n"), LinkedInMapping, "LinkedIn CSV")
+	if err == nil {
+		t.Fatal("expected error for CSV without the mapped columns")
+	}
+}
+
+func TestSplitDuplicates(t *testing.T) {
+	existing := []model.Job{
+		{ID: 1, Title: "Backend Engineer", Company: "Tokopedia"},
+		{ID: 2, Title: "Other", Company: "Other", URL: "https://jobs.example.com/42"},
+	}
+	candidates := []ImportCandidate{
+		{Ref: "line 2", Job: model.Job{Title: "backend engineer ", Company: "TOKOPEDIA"}},
+		{Ref: "line 3", Job: model.Job{Title: "New Title", Company: "New Co", URL: "https://jobs.example.com/42"}},
+		{Ref: "line 4", Job: model.Job{Title: "Data Engineer", Company: "Gojek"}},
+		{Ref: "line 5", Job: model.Job{Title: "Data Engineer", Company: "gojek"}},
+		{Ref: "line 6", Job: model.Job{Title: "Go Developer", Company: "Xendit"}},
+	}
+
+	accepted, duplicates := splitDuplicates(candidates, existing)
+
+	if len(accepted) != 2 || accepted[0].Ref != "line 4" || accepted[1].Ref != "line 6" {
+		t.Fatalf("accepted = %+v, want line 4 and line 6", accepted)
+	}
+
+	want := map[string]string{
+		"line 2": "same title and company as an existing job",
+		"line 3": "same URL as an existing job",
+		"line 5": "duplicate of line 4 in this file",
+	}
+	if len(duplicates) != len(want) {
+		t.Fatalf("duplicates = %+v", duplicates)
+	}
+	for _, dup := range duplicates {
+		if dup.Reason != want[dup.Ref] {
+			t.Errorf("%s reason = %q, want %q", dup.Ref, dup.Reason, want[dup.Ref])
+		}
+	}
+	if duplicates[0].ExistingJobID == nil || *duplicates[0].ExistingJobID != 1 {
+		t.Errorf("line 2 existing job = %v, want 1", duplicates[0].ExistingJobID)
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func deref(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Stage yang dihitung sebagai "perusahaan sudah merespon"
+var responseStages = map[model.JobStatus]bool{
+	model.StatusScreening: true,
+	model.StatusInterview: true,
+	model.StatusOffer:     true,
+	model.StatusRejected:  true,
+}
+
+type DurationStats struct {
+	Count      int      `json:"count"`
+	MedianDays *float64 `json:"median_days"`
+	P90Days    *float64 `json:"p90_days"`
+}
+
+type StageDuration struct {
+	Stage model.JobStatus `json:"stage"`
+	DurationStats
+}
+
+type LatencyReport struct {
+	Jobs                   int             `json:"jobs"`
+	Responded              int             `json:"responded"`
+	ResponseRate           float64         `json:"response_rate"`
+	AppliedToFirstResponse DurationStats   `json:"applied_to_first_response"`
+	TimeInStage            []StageDuration `json:"time_in_stage"`
+	DaysToOffer            DurationStats   `json:"days_to_offer"`
+}
+
+type LatencyFilter struct {
+	Platform string
+	Company  string
+}
+
+// BuildLatencyReport ukur waktu dari JobTimeline.HappenedAt: kapan perusahaan pertama kali respon,
+// berapa lama job diam di tiap stage, dan berapa hari sampai offer.
+func BuildLatencyReport(userID uint, filter LatencyFilter) (*LatencyReport, error) {
+	query := database.DB.Where("user_id = ?", userID)
+	if filter.Platform != "" && filter.Platform != "all" {
+		query = query.Where("platform = ?", filter.Platform)
+	}
+	if filter.Company != "" {
+		// Pakai company yang sudah dinormalisasi kalau ada, jadi "PT Tokopedia" ikut "Tokopedia"
+		if company, err := MatchCompany(userID, filter.Company); err == nil && company != nil {
+			query = query.Where("company_id = ?", company.ID)
+		} else {
+			query = query.Where("LOWER(company) = LOWER(?)", filter.Company)
+		}
+	}
+
+	var jobs []model.Job
+	err := query.Preload("Timelines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("happened_at asc, id asc")
+	}).Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return buildLatencyReport(jobs), nil
+}
+
+func buildLatencyReport(jobs []model.Job) *LatencyReport {
+	var firstResponse, toOffer []float64
+	inStage := map[model.JobStatus][]float64{}
+
+	responded := 0
+	for _, job := range jobs {
+		appliedAt := jobAppliedAt(job)
+
+		if d, ok := firstResponseDays(job); ok {
+			firstResponse = append(firstResponse, d)
+			responded++
+		}
+
+		gotOffer := false
+		for i, t := range job.Timelines {
+			stage := model.JobStatus(t.Stage)
+
+			if !gotOffer && stage == model.StatusOffer {
+				toOffer = append(toOffer, days(appliedAt, t.HappenedAt))
+				gotOffer = true
+			}
+
+			// Durasi stage = sampai entry timeline berikutnya. Stage terakhir masih jalan, jadi tidak dihitung.
+			if i+1 < len(job.Timelines) {
+				inStage[stage] = append(inStage[stage], days(t.HappenedAt, job.Timelines[i+1].HappenedAt))
+			}
+		}
+	}
+
+	report := &LatencyReport{
+		Jobs:                   len(jobs),
+		Responded:              responded,
+		ResponseRate:           ratio(responded, len(jobs)),
+		AppliedToFirstResponse: durationStats(firstResponse),
+		DaysToOffer:            durationStats(toOffer),
+	}
+	for _, stage := range FunnelStages {
+		report.TimeInStage = append(report.TimeInStage, StageDuration{
+			Stage:         stage,
+			DurationStats: durationStats(inStage[stage]),
+		})
+	}
+
+	return report
+}
+
+// jobAppliedAt pakai entry "applied" pertama di timeline kalau ada (hasil import bisa backdated)
+func jobAppliedAt(job model.Job) time.Time {
+	if len(job.Timelines) > 0 && job.Timelines[0].Stage == string(model.StatusApplied) {
+		return job.Timelines[0].HappenedAt
+	}
+	return job.AppliedAt
+}
+
+// firstResponseDays hari dari apply sampai respon pertama perusahaan. Timelines harus urut happened_at.
+func firstResponseDays(job model.Job) (float64, bool) {
+	appliedAt := jobAppliedAt(job)
+	for _, t := range job.Timelines {
+		if responseStages[model.JobStatus(t.Stage)] {
+			return days(appliedAt, t.HappenedAt), true
+		}
+	}
+	return 0, false
+}
+
+func days(from, to time.Time) float64 {
+	d := to.Sub(from).Hours() / 24
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func durationStats(values []float64) DurationStats {
+	stats := DurationStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	median := round1(percentile(sorted, 0.5))
+	p90 := round1(percentile(sorted, 0.9))
+	stats.MedianDays = &median
+	stats.P90Days = &p90
+	return stats
+}
+
+// percentile pakai interpolasi linear, sorted harus sudah urut
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
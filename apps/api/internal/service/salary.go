@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+const defaultBaseCurrency = "IDR"
+
+var ErrInvalidRate = errors.New("rate must be greater than 0")
+
+// Faktor ke gaji per bulan (40 jam/minggu, 5 hari/minggu, 52 minggu/tahun)
+var salaryPeriodsPerMonth = map[string]float64{
+	model.SalaryPeriodHour:  40 * 52 / 12.0,
+	model.SalaryPeriodDay:   5 * 52 / 12.0,
+	model.SalaryPeriodWeek:  52 / 12.0,
+	model.SalaryPeriodMonth: 1,
+	model.SalaryPeriodYear:  1 / 12.0,
+}
+
+// BaseCurrency mata uang tujuan normalisasi gaji, dari SALARY_BASE_CURRENCY (default IDR)
+func BaseCurrency() string {
+	if v := strings.ToUpper(strings.TrimSpace(os.Getenv("SALARY_BASE_CURRENCY"))); v != "" {
+		return v
+	}
+	return defaultBaseCurrency
+}
+
+// NormalizeCurrency validasi kode ISO 4217. Kosong = base currency.
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return BaseCurrency(), nil
+	}
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("invalid currency: %s (use an ISO 4217 code like IDR or USD)", currency)
+	}
+	return currency, nil
+}
+
+// NormalizeSalaryFields rapikan currency/period/type dari input user, parser atau import
+func NormalizeSalaryFields(job *model.Job) error {
+	currency, err := NormalizeCurrency(job.SalaryCurrency)
+	if err != nil {
+		return err
+	}
+	job.SalaryCurrency = currency
+
+	job.SalaryPeriod = strings.ToLower(strings.TrimSpace(job.SalaryPeriod))
+	if job.SalaryPeriod == "" {
+		job.SalaryPeriod = model.SalaryPeriodMonth
+	}
+	if !model.SalaryPeriods[job.SalaryPeriod] {
+		return fmt.Errorf("invalid salary_period: %s (use hour, day, week, month or year)", job.SalaryPeriod)
+	}
+
+	job.SalaryType = strings.ToLower(strings.TrimSpace(job.SalaryType))
+	if job.SalaryType != "" && !model.SalaryTypes[job.SalaryType] {
+		return fmt.Errorf("invalid salary_type: %s (use gross or net)", job.SalaryType)
+	}
+	return nil
+}
+
+// ExchangeRates semua kurs ke base currency, base currency sendiri = 1
+func ExchangeRates() (map[string]float64, error) {
+	var rates []model.ExchangeRate
+	if err := database.DB.Find(&rates).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(rates)+1)
+	for _, r := range rates {
+		result[r.Currency] = r.Rate
+	}
+	result[BaseCurrency()] = 1
+	return result, nil
+}
+
+// convertSalary ubah satu angka gaji ke per bulan dalam base currency. false kalau kurs belum ada.
+func convertSalary(amount *int, currency, period string, rates map[string]float64) (*int, bool) {
+	if amount == nil {
+		return nil, true
+	}
+	rate, ok := rates[currency]
+	if !ok {
+		return nil, false
+	}
+	factor, ok := salaryPeriodsPerMonth[period]
+	if !ok {
+		factor = 1
+	}
+
+	converted := int(math.Round(float64(*amount) * rate * factor))
+	return &converted, true
+}
+
+// ApplySalaryNormalization isi SalaryMin/MaxNormalized pakai rates
+func ApplySalaryNormalization(job *model.Job, rates map[string]float64) {
+	job.SalaryMinNormalized, _ = convertSalary(job.SalaryMin, job.SalaryCurrency, job.SalaryPeriod, rates)
+	job.SalaryMaxNormalized, _ = convertSalary(job.SalaryMax, job.SalaryCurrency, job.SalaryPeriod, rates)
+}
+
+// NormalizeJobSalary — ApplySalaryNormalization dengan kurs terbaru dari database
+func NormalizeJobSalary(job *model.Job) error {
+	rates, err := ExchangeRates()
+	if err != nil {
+		return err
+	}
+	ApplySalaryNormalization(job, rates)
+	return nil
+}
+
+// RenormalizeSalaries hitung ulang gaji normalized untuk job dengan currency tertentu
+// (kosong = semua job bergaji). Dipanggil setelah admin mengubah atau menghapus kurs.
+func RenormalizeSalaries(currency string) error {
+	filter, args := "TRUE", []any{}
+	if currency != "" {
+		filter, args = "jobs.salary_currency = ?", []any{currency}
+	}
+	if err := normalizeSalaries(filter, args...); err != nil {
+		return err
+	}
+
+	// Currency yang kursnya tidak ada lagi jadi tidak ter-normalisasi
+	return database.DB.Exec(`UPDATE jobs SET salary_min_normalized = NULL, salary_max_normalized = NULL
+		WHERE (jobs.salary_min_normalized IS NOT NULL OR jobs.salary_max_normalized IS NOT NULL)
+			AND jobs.salary_currency <> ?
+			AND NOT EXISTS (SELECT 1 FROM exchange_rates r WHERE r.currency = jobs.salary_currency)
+			AND `+filter, append([]any{BaseCurrency()}, args...)...).Error
+}
+
+// BackfillSalaryNormalization isi gaji normalized untuk job lama yang belum pernah dinormalisasi
+func BackfillSalaryNormalization() error {
+	return normalizeSalaries("jobs.salary_min_normalized IS NULL AND jobs.salary_max_normalized IS NULL")
+}
+
+// normalizeSalaries versi SQL dari ApplySalaryNormalization: satu UPDATE untuk semua job bergaji
+// yang cocok dengan filter dan punya kurs. updated_at tidak ikut berubah.
+func normalizeSalaries(filter string, args ...any) error {
+	factor := "CASE jobs.salary_period"
+	var factorArgs []any
+	for _, period := range []string{
+		model.SalaryPeriodHour, model.SalaryPeriodDay, model.SalaryPeriodWeek,
+		model.SalaryPeriodMonth, model.SalaryPeriodYear,
+	} {
+		factor += " WHEN ? THEN ?::float8"
+		factorArgs = append(factorArgs, period, salaryPeriodsPerMonth[period])
+	}
+	factor += " ELSE 1 END"
+
+	base := BaseCurrency()
+	query := `UPDATE jobs SET
+			salary_min_normalized = ROUND((jobs.salary_min * r.rate * ` + factor + `)::numeric),
+			salary_max_normalized = ROUND((jobs.salary_max * r.rate * ` + factor + `)::numeric)
+		FROM (SELECT currency, rate FROM exchange_rates WHERE currency <> ? UNION ALL SELECT ?::text, 1) r
+		WHERE r.currency = jobs.salary_currency
+			AND (jobs.salary_min IS NOT NULL OR jobs.salary_max IS NOT NULL)
+			AND ` + filter
+
+	queryArgs := append(append(append([]any{}, factorArgs...), factorArgs...), base, base)
+	return database.DB.Exec(query, append(queryArgs, args...)...).Error
+}
+
+// SetExchangeRate simpan kurs lalu hitung ulang gaji job dengan currency itu.
+// Kurs yang sudah tersimpan tetap dianggap sukses walau hitung ulangnya gagal; dicoba lagi di background.
+func SetExchangeRate(currency string, rate float64, updatedBy string) (*model.ExchangeRate, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, ErrInvalidRate
+	}
+
+	record := model.ExchangeRate{Currency: currency}
+	err = database.DB.
+		Where(model.ExchangeRate{Currency: currency}).
+		Assign(model.ExchangeRate{Rate: rate, UpdatedBy: updatedBy}).
+		FirstOrCreate(&record).Error
+	if err != nil {
+		return nil, err
+	}
+
+	renormalizeSalariesOrRetry(currency)
+	return &record, nil
+}
+
+// DeleteExchangeRate hapus kurs; gaji job dengan currency itu jadi tidak ter-normalisasi
+func DeleteExchangeRate(currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if err := database.DB.Where("currency = ?", currency).Delete(&model.ExchangeRate{}).Error; err != nil {
+		return err
+	}
+	renormalizeSalariesOrRetry(currency)
+	return nil
+}
+
+func renormalizeSalariesOrRetry(currency string) {
+	if err := RenormalizeSalaries(currency); err != nil {
+		log.Printf("salary renormalization for %s failed, retrying in background: %v", currency, err)
+		InBackground("salary renormalization "+currency, func(ctx context.Context) error {
+			return RenormalizeSalaries(currency)
+		})
+	}
+}
+
+type SalaryStats struct {
+	Jobs   int  `json:"jobs"`
+	Min    *int `json:"min"`
+	Median *int `json:"median"`
+	Avg    *int `json:"avg"`
+	Max    *int `json:"max"`
+}
+
+type GroupSalaryStats struct {
+	Key string `json:"key"`
+	SalaryStats
+}
+
+type SalaryReport struct {
+	Currency    string             `json:"currency"`
+	Period      string             `json:"period"`
+	SalaryStats                    // dari titik tengah min/max tiap job
+	Unconverted map[string]int     `json:"unconverted"` // job bergaji yang kursnya belum ada, per currency
+	ByStatus    []GroupSalaryStats `json:"by_status"`
+	ByPlatform  []GroupSalaryStats `json:"by_platform"`
+	ByCurrency  []GroupSalaryStats `json:"by_currency"` // currency asli lowongan, angkanya tetap dalam base currency
+}
+
+// salaryMidpoint titik tengah gaji normalized, atau salah satunya kalau cuma ada min/max
+func salaryMidpoint(job model.Job) (float64, bool) {
+	switch {
+	case job.SalaryMinNormalized != nil && job.SalaryMaxNormalized != nil:
+		return float64(*job.SalaryMinNormalized+*job.SalaryMaxNormalized) / 2, true
+	case job.SalaryMinNormalized != nil:
+		return float64(*job.SalaryMinNormalized), true
+	case job.SalaryMaxNormalized != nil:
+		return float64(*job.SalaryMaxNormalized), true
+	}
+	return 0, false
+}
+
+func buildSalaryStats(values []float64) SalaryStats {
+	stats := SalaryStats{Jobs: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	round := func(v float64) *int {
+		n := int(math.Round(v))
+		return &n
+	}
+
+	stats.Min = round(sorted[0])
+	stats.Max = round(sorted[len(sorted)-1])
+	stats.Avg = round(sum / float64(len(sorted)))
+	stats.Median = round(percentile(sorted, 0.5))
+	return stats
+}
+
+func buildGroupSalaryStats(groups map[string][]float64) []GroupSalaryStats {
+	result := []GroupSalaryStats{}
+	for key, values := range groups {
+		result = append(result, GroupSalaryStats{Key: key, SalaryStats: buildSalaryStats(values)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Jobs != result[j].Jobs {
+			return result[i].Jobs > result[j].Jobs
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// BuildSalaryReport statistik gaji per bulan dalam base currency, dengan breakdown status/platform/currency
+func BuildSalaryReport(userID uint) (*SalaryReport, error) {
+	var jobs []model.Job
+	err := database.DB.
+		Where("user_id = ? AND (salary_min IS NOT NULL OR salary_max IS NOT NULL)", userID).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SalaryReport{
+		Currency:    BaseCurrency(),
+		Period:      model.SalaryPeriodMonth,
+		Unconverted: map[string]int{},
+	}
+
+	var all []float64
+	byStatus := map[string][]float64{}
+	byPlatform := map[string][]float64{}
+	byCurrency := map[string][]float64{}
+	for _, job := range jobs {
+		mid, ok := salaryMidpoint(job)
+		if !ok {
+			report.Unconverted[job.SalaryCurrency]++
+			continue
+		}
+
+		platform := job.Platform
+		if platform == "" {
+			platform = "unknown"
+		}
+		all = append(all, mid)
+		byStatus[string(job.Status)] = append(byStatus[string(job.Status)], mid)
+		byPlatform[platform] = append(byPlatform[platform], mid)
+		byCurrency[job.SalaryCurrency] = append(byCurrency[job.SalaryCurrency], mid)
+	}
+
+	report.SalaryStats = buildSalaryStats(all)
+	report.ByStatus = buildGroupSalaryStats(byStatus)
+	report.ByPlatform = buildGroupSalaryStats(byPlatform)
+	report.ByCurrency = buildGroupSalaryStats(byCurrency)
+	return report, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+)
+
+func TestConvertSalary(t *testing.T) {
+	rates := map[string]float64{"IDR": 1, "USD": 16000}
+
+	tests := []struct {
+		amount   int
+		currency string
+		period   string
+		want     int
+	}{
+		{15000000, "IDR", model.SalaryPeriodMonth, 15000000},
+		{120000, "USD", model.SalaryPeriodYear, 160000000},
+		{1000, "USD", model.SalaryPeriodWeek, 69333333}, // 1000 * 16000 * 52/12
+		{100, "USD", model.SalaryPeriodDay, 34666667},   // 100 * 16000 * 5*52/12
+		{10, "USD", model.SalaryPeriodHour, 27733333},   // 10 * 16000 * 40*52/12
+		{5000000, "IDR", "fortnight", 5000000},          // period tidak dikenal = per bulan
+	}
+	for _, tt := range tests {
+		amount := tt.amount
+		got, ok := convertSalary(&amount, tt.currency, tt.period, rates)
+		if !ok || got == nil || *got != tt.want {
+			t.Errorf("convertSalary(%d %s/%s) = %v, %v; want %d", tt.amount, tt.currency, tt.period, deref(got), ok, tt.want)
+		}
+	}
+}
+
+func TestConvertSalaryMissingRate(t *testing.T) {
+	amount := 5000
+	got, ok := convertSalary(&amount, "EUR", model.SalaryPeriodMonth, map[string]float64{"IDR": 1})
+	if ok || got != nil {
+		t.Errorf("convertSalary without EUR rate = %v, %v; want nil, false", deref(got), ok)
+	}
+
+	got, ok = convertSalary(nil, "EUR", model.SalaryPeriodMonth, nil)
+	if !ok || got != nil {
+		t.Errorf("convertSalary(nil) = %v, %v; want nil, true", deref(got), ok)
+	}
+}
+
+func TestApplySalaryNormalization(t *testing.T) {
+	job := model.Job{
+		SalaryMin:      intPtr(4000),
+		SalaryMax:      intPtr(6000),
+		SalaryCurrency: "USD",
+		SalaryPeriod:   model.SalaryPeriodMonth,
+	}
+	ApplySalaryNormalization(&job, map[string]float64{"IDR": 1, "USD": 16000})
+	if deref(job.SalaryMinNormalized) != 64000000 || deref(job.SalaryMaxNormalized) != 96000000 {
+		t.Errorf("normalized = %v - %v", deref(job.SalaryMinNormalized), deref(job.SalaryMaxNormalized))
+	}
+
+	// Kurs dihapus → normalized dikosongkan lagi
+	ApplySalaryNormalization(&job, map[string]float64{"IDR": 1})
+	if job.SalaryMinNormalized != nil || job.SalaryMaxNormalized != nil {
+		t.Errorf("normalized without rate = %v - %v, want nil", deref(job.SalaryMinNormalized), deref(job.SalaryMaxNormalized))
+	}
+}
+
+func TestNormalizeSalaryFields(t *testing.T) {
+	t.Setenv("SALARY_BASE_CURRENCY", "")
+
+	job := model.Job{SalaryCurrency: " usd ", SalaryPeriod: "YEAR", SalaryType: " Gross "}
+	if err := NormalizeSalaryFields(&job); err != nil {
+		t.Fatalf("NormalizeSalaryFields: %v", err)
+	}
+	if job.SalaryCurrency != "USD" || job.SalaryPeriod != model.SalaryPeriodYear || job.SalaryType != model.SalaryTypeGross {
+		t.Errorf("normalized fields = %s/%s/%s", job.SalaryCurrency, job.SalaryPeriod, job.SalaryType)
+	}
+
+	empty := model.Job{}
+	if err := NormalizeSalaryFields(&empty); err != nil {
+		t.Fatalf("NormalizeSalaryFields(empty): %v", err)
+	}
+	if empty.SalaryCurrency != "IDR" || empty.SalaryPeriod != model.SalaryPeriodMonth {
+		t.Errorf("defaults = %s/%s, want IDR/month", empty.SalaryCurrency, empty.SalaryPeriod)
+	}
+
+	for _, bad := range []model.Job{
+		{SalaryCurrency: "Rupiah"},
+		{SalaryPeriod: "fortnight"},
+		{SalaryType: "take-home"},
+	} {
+		if err := NormalizeSalaryFields(&bad); err == nil {
+			t.Errorf("NormalizeSalaryFields(%+v) expected error", bad)
+		}
+	}
+}
+
+func TestBuildSalaryStats(t *testing.T) {
+	stats := buildSalaryStats([]float64{30, 10, 20, 41})
+	if stats.Jobs != 4 || deref(stats.Min) != 10 || deref(stats.Max) != 41 || deref(stats.Avg) != 25 || deref(stats.Median) != 25 {
+		t.Errorf("stats = %d jobs, min %v, max %v, avg %v, median %v",
+			stats.Jobs, deref(stats.Min), deref(stats.Max), deref(stats.Avg), deref(stats.Median))
+	}
+
+	if empty := buildSalaryStats(nil); empty.Jobs != 0 || empty.Min != nil || empty.Median != nil {
+		t.Errorf("empty stats = %+v", empty)
+	}
+}
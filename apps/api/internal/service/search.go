@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+type JobMatch struct {
+	model.Job
+	Similarity   float64 `json:"similarity"`
+	MatchedField string  `json:"matched_field"`
+}
+
+// SemanticSearch embed query lalu urutkan job user berdasarkan cosine distance
+// ke description/requirements. Tiap job cuma muncul sekali dengan field paling mirip.
+func SemanticSearch(ctx context.Context, userID uint, query string, limit int) ([]JobMatch, error) {
+	embedding, err := ai.GetEmbedding(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	vector := model.Vector(embedding.Vector)
+
+	var matches []JobMatch
+	err = database.DB.Raw(`
+		SELECT * FROM (
+			SELECT DISTINCT ON (jobs.id) jobs.*,
+				1 - (je.vector <=> ?::vector) AS similarity,
+				je.field AS matched_field
+			FROM jobs
+			JOIN job_embeddings je ON je.job_id = jobs.id
+			WHERE jobs.user_id = ?
+			  AND jobs.deleted_at IS NULL
+			  AND je.model = ?
+			ORDER BY jobs.id, je.vector <=> ?::vector
+		) ranked
+		ORDER BY similarity DESC
+		LIMIT ?`,
+		vector, userID, embedding.Model, vector, limit,
+	).Scan(&matches).Error
+
+	return matches, err
+}
+
+// MissingJobEmbeddings = job yang punya teks tapi belum di-embed dengan model aktif
+func MissingJobEmbeddings(userID uint) ([]model.Job, error) {
+	e, err := ai.CurrentEmbedder()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []model.Job
+	err = database.DB.
+		Where("user_id = ?", userID).
+		Where("requirements <> '' OR description <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM job_embeddings je WHERE je.job_id = jobs.id AND je.model = ?)", e.ModelID()).
+		Find(&jobs).Error
+
+	return jobs, err
+}
+
+var backfilling sync.Map
+
+// BackfillJobEmbeddings embed job lama yang dibuat sebelum embedding disimpan.
+// Satu user cuma boleh punya satu backfill yang jalan.
+func BackfillJobEmbeddings(ctx context.Context, userID uint, jobs []model.Job) error {
+	if _, running := backfilling.LoadOrStore(userID, true); running {
+		return nil
+	}
+	defer backfilling.Delete(userID)
+
+	for i := range jobs {
+		if err := RefreshJob(ctx, &jobs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+)
+
+// Subset dari board JSON export Trello yang kita butuhkan
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"lists"`
+	Cards []struct {
+		ID     string     `json:"id"`
+		Name   string     `json:"name"`
+		Desc   string     `json:"desc"`
+		IDList string     `json:"idList"`
+		Closed bool       `json:"closed"`
+		Due    *time.Time `json:"due"`
+		URL    string     `json:"url"`
+	} `json:"cards"`
+	Actions []trelloAction `json:"actions"`
+}
+
+type trelloAction struct {
+	Type string    `json:"type"`
+	Date time.Time `json:"date"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			ID string `json:"id"`
+		} `json:"card"`
+		List       *trelloRef `json:"list"`
+		ListBefore *trelloRef `json:"listBefore"`
+		ListAfter  *trelloRef `json:"listAfter"`
+	} `json:"data"`
+}
+
+type trelloRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TrelloMapping — list Trello (nama atau ID) → JobStatus
+type TrelloMapping struct {
+	Lists           map[string]string `json:"lists"`
+	NameFormat      string            `json:"name_format"` // "company - title" (default), "title - company", "title @ company"
+	IncludeArchived bool              `json:"include_archived"`
+	Platform        string            `json:"platform"`
+}
+
+func (m TrelloMapping) Validate() error {
+	if len(m.Lists) == 0 {
+		return fmt.Errorf("mapping.lists must map at least one Trello list to a status")
+	}
+	for list, status := range m.Lists {
+		if !model.JobStatus(status).IsValid() {
+			return fmt.Errorf("lists[%q]: invalid status %q", list, status)
+		}
+	}
+	switch m.NameFormat {
+	case "", "company - title", "title - company", "title @ company":
+		return nil
+	default:
+		return fmt.Errorf("unknown name_format %q", m.NameFormat)
+	}
+}
+
+func (m TrelloMapping) statusFor(ref *trelloRef) (model.JobStatus, bool) {
+	if ref == nil {
+		return "", false
+	}
+	if s, ok := m.Lists[ref.ID]; ok {
+		return model.JobStatus(s), true
+	}
+	for name, s := range m.Lists {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(ref.Name)) {
+			return model.JobStatus(s), true
+		}
+	}
+	return "", false
+}
+
+// ParseTrello ubah kartu jadi job. Perpindahan list di actions jadi histori timeline
+// dengan timestamp aslinya, komentar kartu masuk ke Notes.
+func ParseTrello(r io.Reader, mapping TrelloMapping) ([]ImportCandidate, []ImportIssue, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, nil, fmt.Errorf("invalid Trello export: %w", err)
+	}
+
+	lists := map[string]*trelloRef{}
+	for _, l := range board.Lists {
+		lists[l.ID] = &trelloRef{ID: l.ID, Name: l.Name}
+	}
+
+	// Trello export urut terbaru dulu, kita butuh kronologis
+	actionsByCard := map[string][]trelloAction{}
+	for _, a := range board.Actions {
+		actionsByCard[a.Data.Card.ID] = append(actionsByCard[a.Data.Card.ID], a)
+	}
+	for id := range actionsByCard {
+		actions := actionsByCard[id]
+		sort.SliceStable(actions, func(i, j int) bool { return actions[i].Date.Before(actions[j].Date) })
+	}
+
+	platform := mapping.Platform
+	if platform == "" {
+		platform = "other"
+	}
+
+	var candidates []ImportCandidate
+	var issues []ImportIssue
+	for _, card := range board.Cards {
+		ref := "card " + card.ID
+		if card.Closed && !mapping.IncludeArchived {
+			continue
+		}
+
+		list := lists[card.IDList]
+		status, ok := mapping.statusFor(list)
+		if !ok {
+			name := card.IDList
+			if list != nil {
+				name = list.Name
+			}
+			issues = append(issues, ImportIssue{Ref: ref, Message: fmt.Sprintf("list %q is not mapped to a status", name)})
+			continue
+		}
+
+		title, company, err := splitTrelloCardName(card.Name, mapping.NameFormat)
+		if err != nil {
+			issues = append(issues, ImportIssue{Ref: ref, Message: err.Error()})
+			continue
+		}
+
+		// Entry pertama dari list tempat kartu dibuat, kartu bisa langsung dibuat di list "Interview"
+		actions := actionsByCard[card.ID]
+		appliedAt := trelloCardCreatedAt(card.ID)
+		current := model.StatusApplied
+		note := "Imported from Trello board " + board.Name
+		for _, a := range actions {
+			if a.Type == "createCard" {
+				appliedAt = a.Date
+				if initial, ok := mapping.statusFor(a.Data.List); ok {
+					current = initial
+					note = fmt.Sprintf("Created in %q on Trello board %s", a.Data.List.Name, board.Name)
+				}
+				break
+			}
+		}
+
+		var comments []string
+		timelines := []model.JobTimeline{{
+			Stage:      string(current),
+			Note:       note,
+			HappenedAt: appliedAt,
+		}}
+		for _, a := range actions {
+			switch {
+			case a.Type == "commentCard" && a.Data.Text != "":
+				comments = append(comments, a.Date.Format("2006-01-02")+": "+a.Data.Text)
+			case a.Type == "updateCard" && a.Data.ListAfter != nil:
+				next, ok := mapping.statusFor(a.Data.ListAfter)
+				if !ok || next == current {
+					continue
+				}
+				before := ""
+				if a.Data.ListBefore != nil {
+					before = a.Data.ListBefore.Name
+				}
+				timelines = append(timelines, model.JobTimeline{
+					Stage:      string(next),
+					Note:       fmt.Sprintf("Moved from %q to %q in Trello", before, a.Data.ListAfter.Name),
+					HappenedAt: a.Date,
+				})
+				current = next
+			}
+		}
+
+		job := model.Job{
+			Title:       title,
+			Company:     company,
+			URL:         card.URL,
+			Platform:    platform,
+			Status:      status,
+			Description: card.Desc,
+			Notes:       strings.Join(comments, "\n"),
+			AppliedAt:   appliedAt,
+			Deadline:    card.Due,
+		}
+
+		candidates = append(candidates, ImportCandidate{Ref: ref, Job: job, Timelines: timelines})
+	}
+
+	return candidates, issues, nil
+}
+
+func splitTrelloCardName(name, format string) (title, company string, err error) {
+	name = strings.TrimSpace(name)
+
+	sep, titleFirst := " - ", false
+	switch format {
+	case "title - company":
+		titleFirst = true
+	case "title @ company":
+		sep, titleFirst = " @ ", true
+	}
+
+	parts := strings.SplitN(name, sep, 2)
+	if len(parts) != 2 && format == "" {
+		// Fallback format "Backend Engineer @ Tokopedia"
+		parts, titleFirst = strings.SplitN(name, " @ ", 2), true
+	}
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return "", "", fmt.Errorf("cannot split %q into title and company", name)
+	}
+
+	if titleFirst {
+		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+	}
+	return strings.TrimSpace(parts[1]), strings.TrimSpace(parts[0]), nil
+}
+
+// trelloCardCreatedAt — 8 karakter hex pertama ID Trello adalah unix timestamp pembuatan
+func trelloCardCreatedAt(id string) time.Time {
+	if len(id) >= 8 {
+		if sec, err := strconv.ParseInt(id[:8], 16, 64); err == nil {
+			return time.Unix(sec, 0)
+		}
+	}
+	return time.Now()
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/myfarism/lamarr-api/internal/model"
+)
+
+func TestParseTrelloTimeline(t *testing.T) {
+	board := `{
+		"name": "Job Hunt",
+		"lists": [{"id": "l1", "name": "Applied"}, {"id": "l2", "name": "Interview"}, {"id": "l3", "name": "Offer"}],
+		"cards": [
+			{"id": "c1", "name": "Acme - Go Dev", "idList": "l3"},
+			{"id": "c2", "name": "Globex - Data Engineer", "idList": "l2"}
+		],
+		"actions": [
+			{"type": "updateCard", "date": "2026-02-10T00:00:00Z", "data": {"card": {"id": "c1"}, "listBefore": {"id": "l2", "name": "Interview"}, "listAfter": {"id": "l3", "name": "Offer"}}},
+			{"type": "createCard", "date": "2026-02-01T00:00:00Z", "data": {"card": {"id": "c1"}, "list": {"id": "l2", "name": "Interview"}}},
+			{"type": "createCard", "date": "2026-01-15T00:00:00Z", "data": {"card": {"id": "c2"}, "list": {"id": "l1", "name": "Applied"}}}
+		]
+	}`
+	mapping := TrelloMapping{Lists: map[string]string{"Applied": "applied", "Interview": "interview", "Offer": "offer"}}
+
+	candidates, issues, err := ParseTrello(strings.NewReader(board), mapping)
+	if err != nil {
+		t.Fatalf("ParseTrello: %v", err)
+	}
+	if len(candidates) != 2 || len(issues) != 0 {
+		t.Fatalf("got %d candidates, issues %+v", len(candidates), issues)
+	}
+
+	// Kartu dibuat langsung di list Interview → entry pertama interview, bukan applied
+	first := candidates[0].Timelines
+	if len(first) != 2 || first[0].Stage != string(model.StatusInterview) || first[1].Stage != string(model.StatusOffer) {
+		t.Fatalf("c1 timelines = %+v, want interview then offer", first)
+	}
+	if got := first[0].HappenedAt.Format("2006-01-02"); got != "2026-02-01" {
+		t.Errorf("c1 first entry at %s, want 2026-02-01", got)
+	}
+
+	second := candidates[1].Timelines
+	if len(second) != 1 || second[0].Stage != string(model.StatusApplied) {
+		t.Errorf("c2 timelines = %+v, want only the applied entry", second)
+	}
+}
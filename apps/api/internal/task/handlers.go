@@ -0,0 +1,133 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hibiken/asynq"
+	"github.com/myfarism/lamarr-api/internal/ai"
+	"github.com/myfarism/lamarr-api/internal/model"
+	"github.com/myfarism/lamarr-api/internal/service"
+	"github.com/myfarism/lamarr-api/pkg/database"
+)
+
+// Register daftarkan semua handler task ke mux worker
+func Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TypeParse, HandleParse)
+	mux.HandleFunc(TypeScrape, HandleScrape)
+	mux.HandleFunc(TypeAnalyze, HandleAnalyze)
+	mux.HandleFunc(TypeRescore, HandleRescore)
+	mux.HandleFunc(TypeGhostSweep, HandleGhostSweep)
+	mux.HandleFunc(TypeCachePurge, HandleCachePurge)
+}
+
+func HandleParse(ctx context.Context, t *asynq.Task) error {
+	var p ParsePayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	parsed, err := ai.ParseJobDescription(ctx, p.Text)
+	if err != nil {
+		return err
+	}
+	service.MatchParsedCompany(p.UserID, parsed)
+	service.SaveParseResult(p.UserID, parsed)
+
+	return writeResult(t, parsed)
+}
+
+func HandleScrape(ctx context.Context, t *asynq.Task) error {
+	var p ScrapePayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	parsed, err := service.ScrapeAndParse(ctx, p.URL)
+	if errors.Is(err, service.ErrNoContent) {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	if err != nil {
+		return err
+	}
+	service.MatchParsedCompany(p.UserID, parsed)
+	service.SaveParseResult(p.UserID, parsed)
+
+	return writeResult(t, parsed)
+}
+
+func HandleAnalyze(ctx context.Context, t *asynq.Task) error {
+	var p AnalyzePayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	var user model.User
+	if err := database.DB.First(&user, p.UserID).Error; err != nil {
+		return fmt.Errorf("user not found: %w", asynq.SkipRetry)
+	}
+
+	var job model.Job
+	if err := database.DB.Where("id = ? AND user_id = ?", p.JobID, p.UserID).First(&job).Error; err != nil {
+		return fmt.Errorf("job not found: %w", asynq.SkipRetry)
+	}
+
+	analysis, err := service.AnalyzeJob(ctx, user, &job, p.Language)
+	if errors.Is(err, service.ErrNoCV) || errors.Is(err, service.ErrNoRequirements) {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeResult(t, analysis)
+}
+
+func HandleRescore(ctx context.Context, t *asynq.Task) error {
+	var p RescorePayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("invalid payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if err := service.RescoreUser(ctx, p.UserID); err != nil {
+		return err
+	}
+
+	return writeResult(t, map[string]any{"rescored": true})
+}
+
+func writeResult(t *asynq.Task, result any) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = t.ResultWriter().Write(body)
+	return err
+}
+
+func HandleGhostSweep(ctx context.Context, t *asynq.Task) error {
+	n, err := service.SweepGhostedJobs(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("👻 ghost sweep: %d jobs marked as ghosted", n)
+	}
+
+	return writeResult(t, map[string]any{"ghosted": n})
+}
+
+func HandleCachePurge(ctx context.Context, t *asynq.Task) error {
+	n, err := service.PurgeAICache(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("🧹 ai cache purge: %d expired entries deleted", n)
+	}
+
+	return writeResult(t, map[string]any{"deleted": n})
+}
@@ -0,0 +1,61 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/myfarism/lamarr-api/pkg/queue"
+)
+
+const (
+	TypeParse   = "ai:parse"
+	TypeScrape  = "ai:scrape"
+	TypeAnalyze = "ai:analyze"
+	TypeRescore = "ai:rescore"
+
+	TypeGhostSweep = "maintenance:ghost-sweep"
+	TypeCachePurge = "maintenance:ai-cache-purge"
+)
+
+// Owner — semua payload punya user_id, dipakai endpoint status buat cek kepemilikan task
+type Owner struct {
+	UserID uint `json:"user_id"`
+}
+
+type ParsePayload struct {
+	UserID uint   `json:"user_id"`
+	Text   string `json:"text"`
+}
+
+type ScrapePayload struct {
+	UserID uint   `json:"user_id"`
+	URL    string `json:"url"`
+}
+
+type AnalyzePayload struct {
+	UserID   uint   `json:"user_id"`
+	JobID    uint   `json:"job_id"`
+	Language string `json:"language,omitempty"`
+}
+
+type RescorePayload struct {
+	UserID uint `json:"user_id"`
+}
+
+// Enqueue masukkan task ke queue AI. Hasil disimpan 24 jam supaya bisa di-poll.
+func Enqueue(ctx context.Context, taskType string, payload any) (*asynq.TaskInfo, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return queue.Client.EnqueueContext(ctx,
+		asynq.NewTask(taskType, body),
+		asynq.Queue(queue.QueueAI),
+		asynq.MaxRetry(3),
+		asynq.Timeout(2*time.Minute),
+		asynq.Retention(24*time.Hour),
+	)
+}
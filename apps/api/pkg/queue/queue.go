@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"log"
+	"os"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	QueueAI          = "ai"          // semua task AI
+	QueueMaintenance = "maintenance" // task terjadwal (ghost sweep, dll)
+)
+
+var (
+	RedisOpt  asynq.RedisConnOpt
+	Client    *asynq.Client
+	Inspector *asynq.Inspector
+)
+
+// Connect — kalau REDIS_URL kosong, queue dimatikan dan endpoint async balikin 503
+func Connect() {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		log.Println("REDIS_URL is not set, background queue disabled")
+		return
+	}
+
+	opt, err := asynq.ParseRedisURI(redisURL)
+	if err != nil {
+		log.Fatalf("Failed to parse REDIS_URL: %v", err)
+	}
+
+	RedisOpt = opt
+	Client = asynq.NewClient(opt)
+	Inspector = asynq.NewInspector(opt)
+	log.Println("✅ Task queue connected")
+}
+
+func Enabled() bool {
+	return Client != nil
+}
+
+// Scheduled true kalau ada scheduler Asynq (cmd/worker) yang sedang jalan dan mendaftarkan taskType
+func Scheduled(taskType string) bool {
+	if Inspector == nil {
+		return false
+	}
+	entries, err := Inspector.SchedulerEntries()
+	if err != nil {
+		log.Printf("failed to list scheduler entries: %v", err)
+		return false
+	}
+	for _, e := range entries {
+		if e.Task != nil && e.Task.Type() == taskType {
+			return true
+		}
+	}
+	return false
+}